@@ -3,17 +3,27 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
-	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/source"
 	"github.com/spf13/cobra"
 )
 
-var dest string // may be empty; “auto” when omitted
+var (
+	dest     string   // may be empty; “auto” when omitted
+	worlds   []string // datapacks only; empty = every world
+	addType  string   // content type for non-Modrinth sources
+	addName  string   // entry name for non-Modrinth sources
+	addSHA1  string   // required for url sources
+	addTag   string   // github release tag
+	addAsset string   // github asset glob
+	addRepo  string   // maven repository url
+)
 
 var addCmd = &cobra.Command{
-	Use:   "add <slug|url>",
-	Short: "Add a Modrinth project to the manifest",
+	Use:   "add <slug|url|cf:slug|gh:owner/repo|maven:coords|file:path>",
+	Short: "Add a Modrinth or CurseForge project, direct download, GitHub release, Maven artifact or local file to the manifest",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := filepath.Join(gameDir, manifestRel)
@@ -23,31 +33,50 @@ var addCmd = &cobra.Command{
 			return err
 		}
 
-		slug := modrinth.ParseSlug(args[0])
-		fmt.Printf("Resolving %s...\n", slug)
+		var slug string
+		if e, ok := customEntry(args[0]); ok {
+			ct, ok := manifest.TypeForProject(addType)
+			if !ok {
+				return fmt.Errorf("unknown --type %q", addType)
+			}
+			e.Dest = dest
+			if err := m.AddEntry(ct, e); err != nil {
+				return err
+			}
+			slug = e.Slug
+		} else {
+			name, id := splitProvider(args[0])
+			p, err := openProvider(name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Resolving %s...\n", id)
 
-		if err := m.Add(cmd.Context(), slug, dest); err != nil {
+			if slug, err = m.Add(cmd.Context(), p, id, dest); err != nil {
+				return err
+			}
+		}
+		if len(worlds) > 0 {
+			ct, e := m.Find(slug)
+			if e == nil || !ct.PerWorld {
+				return fmt.Errorf("--world only applies to datapacks")
+			}
+			e.Worlds = worlds
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		if err := checkLicenses(cmd.Context(), inst, []string{slug}); err != nil {
 			return err
 		}
 		if err := m.Save(); err != nil {
 			return err
 		}
 		if dest == "" {
-			// manifest.Add chose the folder; look it up to print a nice message
-			for _, e := range m.Enabled() {
-				if e.Slug == slug {
-					dest = e.Dest
-				}
-			}
-			for _, e := range m.ResourcePacks {
-				if e.Slug == slug {
-					dest = e.Dest
-				}
-			}
-			for _, e := range m.Shaders {
-				if e.Slug == slug {
-					dest = e.Dest
-				}
+			// the manifest chose the folder; look it up to print a nice message
+			if _, e := m.Find(slug); e != nil {
+				dest = e.Dest
 			}
 			if dest == "" {
 				return fmt.Errorf("could not determine destination for %s", slug)
@@ -58,6 +87,44 @@ var addCmd = &cobra.Command{
 	},
 }
 
+// @brief customEntry builds a manifest entry for a non-Modrinth argument.
+// @param arg "gh:owner/repo", "maven:group:artifact[:version]", "file:path" or an https URL outside modrinth.com
+// @return the entry and true, or false if arg is a Modrinth slug/URL
+func customEntry(arg string) (manifest.Entry, bool) {
+	e := manifest.Entry{Slug: addName, Enable: true}
+	switch {
+	case strings.HasPrefix(arg, "gh:"):
+		e.Source = source.GitHub
+		e.Repo = strings.TrimPrefix(arg, "gh:")
+		e.Tag = addTag
+		e.Asset = addAsset
+		if e.Slug == "" {
+			e.Slug = filepath.Base(e.Repo)
+		}
+	case strings.HasPrefix(arg, "maven:"):
+		e.Source = source.Maven
+		e.Maven = strings.TrimPrefix(arg, "maven:")
+		e.Repository = addRepo
+		if co, err := source.ParseCoords(e.Maven); err == nil && e.Slug == "" {
+			e.Slug = co.Artifact
+		}
+	case strings.HasPrefix(arg, "file:"):
+		e.Source = source.File
+		e.Path = strings.TrimPrefix(arg, "file:")
+	case strings.Contains(arg, "://") && !strings.Contains(arg, "modrinth.com/"):
+		e.Source = source.URL
+		e.URL = arg
+		e.Checksum = strings.ToLower(addSHA1)
+	default:
+		return manifest.Entry{}, false
+	}
+	if e.Slug == "" && e.Maven == "" {
+		name := filepath.Base(e.Path + e.URL) // one of them is empty
+		e.Slug = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return e, true
+}
+
 func init() {
 	addCmd.Flags().StringVar(
 		&dest,
@@ -65,4 +132,16 @@ func init() {
 		"",
 		"destination folder (mods, resourcepacks, shaderpacks). Leave blank to infer from project type.",
 	)
+	addCmd.Flags().StringSliceVar(
+		&worlds,
+		"world",
+		nil,
+		"world(s) to install a datapack into (repeatable). Leave blank for every world.",
+	)
+	addCmd.Flags().StringVar(&addType, "type", "mod", "content type for url/gh:/maven:/file: entries (mod, plugin, resourcepack, shader, datapack)")
+	addCmd.Flags().StringVar(&addName, "name", "", "entry name for url/gh:/maven:/file: entries (default: derived from the file or repo)")
+	addCmd.Flags().StringVar(&addSHA1, "sha1", "", "expected SHA1 of a url entry (required)")
+	addCmd.Flags().StringVar(&addTag, "tag", "", "GitHub release tag to pin (default: latest release)")
+	addCmd.Flags().StringVar(&addAsset, "asset", "", "GitHub asset name glob (default: *.jar)")
+	addCmd.Flags().StringVar(&addRepo, "repo", "", "Maven repository url for maven: entries (e.g. https://maven.fabricmc.net/)")
 }
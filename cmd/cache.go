@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/cache"
+	"github.com/silask7188/ModrinthCLI/internal/config"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var cacheDryRun bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache shared by every game dir",
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show where the cache is and how much it holds",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+		s, err := c.Stats()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cache:     %s\n", c.Dir())
+		fmt.Printf("Files:     %d (%s)\n", s.Files, byteSize(s.Bytes))
+		fmt.Printf("Manifests: %d\n", s.Manifests)
+		return nil
+	},
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Hash every cached file and drop the corrupt ones",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+		bad, err := c.Verify()
+		for _, sum := range bad {
+			fmt.Printf("[-] %s removed (hash mismatch)\n", sum)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cache verified, %d corrupt files removed\n", len(bad))
+		return nil
+	},
+}
+
+var cacheGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove cached files that no known manifest references",
+	Long: `Remove cached files that no known manifest references. A manifest is
+known once an install through it has used the cache; manifests that no longer
+exist are forgotten.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+		known, err := c.Manifests()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(gameDir, manifestRel)); err == nil {
+			known = append(known, filepath.Join(gameDir, manifestRel))
+		}
+
+		keep := map[string]bool{}
+		for _, path := range known {
+			m, err := manifest.Load(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w (fix or delete it before gc)", path, err)
+			}
+			for _, ct := range manifest.ContentTypes() {
+				for _, e := range m.Entries(ct) {
+					for _, sum := range []string{e.Checksum, e.SHA512} {
+						if sum != "" {
+							keep[strings.ToLower(sum)] = true
+						}
+					}
+				}
+			}
+		}
+
+		n, freed, err := c.GC(keep, cacheDryRun)
+		if err != nil {
+			return err
+		}
+		if cacheDryRun {
+			fmt.Printf("Would remove %d files (%s)\n", n, byteSize(freed))
+		} else {
+			fmt.Printf("Removed %d files (%s)\n", n, byteSize(freed))
+		}
+		return nil
+	},
+}
+
+// @brief openCache opens the cache the user's config points at.
+// @return cache or error if it is disabled or cannot be created
+func openCache() (*cache.Cache, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	c, err := cache.FromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		path, _ := config.Path()
+		return nil, fmt.Errorf("the download cache is disabled in %s", path)
+	}
+	return c, nil
+}
+
+// @brief byteSize formats a size in bytes for people.
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	cacheGCCmd.Flags().BoolVar(&cacheDryRun, "dry-run", false, "only report what would be removed")
+	cacheCmd.AddCommand(cacheInfoCmd, cacheVerifyCmd, cacheGCCmd)
+}
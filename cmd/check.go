@@ -4,21 +4,22 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/silask7188/ModrinthCLI/internal/installer"
+	"github.com/silask7188/ModrinthCLI/internal/jarmeta"
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
 var checkCmd = &cobra.Command{
-	Use:   "check",
-	Short: "Check for issues in the manifest",
+	Use:     "check",
+	Short:   "Check the manifest and the jars in mods/ for problems",
+	Aliases: []string{"doctor"},
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
 		if err != nil {
 			return fmt.Errorf("failed to load manifest: %w", err)
 		}
 
-		inst, err := installer.New(gameDir, m)
+		inst, err := newInstaller(gameDir, m)
 		if err != nil {
 			return fmt.Errorf("failed to create installer: %w", err)
 		}
@@ -30,18 +31,47 @@ var checkCmd = &cobra.Command{
 		if err != nil {
 			fmt.Fprintf(cmd.OutOrStdout(), "something wrong! : %v\n", err)
 		}
-		if len(res) == 0 {
+		issues, err := jarIssues(m)
+		if err != nil {
+			return fmt.Errorf("failed to scan mods: %w", err)
+		}
+		if len(res) == 0 && len(issues) == 0 {
 			fmt.Fprintln(cmd.OutOrStdout(), "Manifest is valid ✓")
-		} else {
-			for _, r := range res {
-				fmt.Fprint(cmd.OutOrStdout(), r)
-			}
+			return nil
+		}
+		for _, r := range res {
+			fmt.Fprint(cmd.OutOrStdout(), r)
+		}
+		if len(res) > 0 {
 			print("Please fix the issues above and try again.\nIf the file was renamed, you are fine.\n")
 		}
+		for _, is := range issues {
+			fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", is.Kind, is.Message)
+		}
 		return nil
 	},
 }
 
+// @brief jarIssues reads every enabled jar in the mods folder, tracked or not,
+// and looks for duplicate ids, unmet dependencies and loader mismatches.
+// @param m manifest, for entry names and the expected versions
+// @return problems found, or error if the folder could not be read
+func jarIssues(m *manifest.Manifest) ([]jarmeta.Issue, error) {
+	ct, _ := manifest.TypeForSection("mods")
+	owners := map[string]string{}
+	for _, e := range m.Entries(ct) {
+		if e.Filename != "" && e.Dest == ct.Dest {
+			owners[e.Filename] = e.Slug
+		}
+	}
+	jars, issues, err := jarmeta.Scan(filepath.Join(gameDir, ct.Dest), owners)
+	if err != nil {
+		return nil, err
+	}
+	env := jarmeta.Env{Minecraft: m.Minecraft.Version, Loader: m.Minecraft.Loader, LoaderVersion: m.Minecraft.LoaderVersion}
+	return append(issues, jarmeta.Analyze(jars, env)...), nil
+}
+
 func init() {
 	checkCmd.SilenceUsage = true
 }
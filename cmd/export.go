@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/modlist"
+	"github.com/silask7188/ModrinthCLI/internal/serverpack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut           string
+	exportName          string
+	exportVersion       string
+	exportLoaderVersion string
+	listFormat          string
+	listTemplate        string
+	listRefresh         bool
+	listPrintTemplate   bool
+	serverJVMArgs       string
+	serverNoLauncher    bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the manifest in other formats",
+}
+
+var exportMrpackCmd = &cobra.Command{
+	Use:   "mrpack",
+	Short: "Write a Modrinth modpack (.mrpack) from the installed entries",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+
+		name := exportName
+		if name == "" {
+			abs, _ := filepath.Abs(m.Dir())
+			name = filepath.Base(abs)
+		}
+		if err := checkLicenses(cmd.Context(), inst, enabledSlugs(m)); err != nil {
+			return err
+		}
+		pack, err := inst.Mrpack(cmd.Context(), name, exportVersion, exportLoaderVersion)
+		if err != nil {
+			return err
+		}
+
+		out := exportOut
+		if out == "" {
+			out = fmt.Sprintf("%s-%s.mrpack", name, exportVersion)
+		}
+		if err := pack.Write(out); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		fmt.Printf("Wrote %s (%d linked, %d embedded)\n", out, len(pack.Index.Files), len(pack.Embedded))
+		return nil
+	},
+}
+
+var exportPackwizCmd = &cobra.Command{
+	Use:   "packwiz <dir>",
+	Short: "Write a packwiz pack (pack.toml, index.toml, .pw.toml metafiles)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+
+		name := exportName
+		if name == "" {
+			abs, _ := filepath.Abs(m.Dir())
+			name = filepath.Base(abs)
+		}
+		pack, err := inst.Packwiz(cmd.Context(), name, exportVersion)
+		if err != nil {
+			return err
+		}
+		if err := pack.Write(args[0]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", args[0], err)
+		}
+		fmt.Printf("Wrote %s (%d metafiles, %d files)\n", args[0], len(pack.Metafiles), len(pack.Files))
+		return nil
+	},
+}
+
+var exportListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Write a shareable list of the enabled entries (Markdown, HTML or CSV)",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if listPrintTemplate {
+			t, err := modlist.Template(listFormat)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), t)
+			return nil
+		}
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		prjs, err := inst.Projects(cmd.Context(), listRefresh)
+		if err != nil {
+			return err
+		}
+
+		name := exportName
+		if name == "" {
+			name = "Modlist"
+			if m.Modpack != nil {
+				name = m.Modpack.Name
+			}
+		}
+		var w io.Writer = cmd.OutOrStdout()
+		if exportOut != "" {
+			f, err := os.Create(exportOut)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		return modlist.Render(w, listFormat, listTemplate, modlist.Build(m, name, prjs))
+	},
+}
+
+var exportServerPackCmd = &cobra.Command{
+	Use:   "server-pack",
+	Short: "Write a dedicated server folder or zip: server-side entries, server overrides, launcher and start scripts",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		out := exportOut
+		if out == "" {
+			out = "server-pack"
+		}
+		if err := serverpack.CheckDest(out); err != nil {
+			return err
+		}
+		if err := serverpack.CheckJVMArgs(serverJVMArgs); err != nil {
+			return err
+		}
+		pack, err := inst.ServerPack(cmd.Context(), exportLoaderVersion, !serverNoLauncher)
+		if err != nil {
+			return err
+		}
+		defer pack.Close()
+		pack.JVMArgs = serverJVMArgs
+		if err := checkLicenses(cmd.Context(), inst, pack.Included); err != nil {
+			return err
+		}
+
+		if err := pack.Write(out); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		for _, slug := range pack.Skipped {
+			fmt.Printf("[-] %s skipped (client-only)\n", slug)
+		}
+		fmt.Printf("Wrote %s (%d files, %s launcher)\n", out, len(pack.Files), pack.Launcher.Loader)
+		return nil
+	},
+}
+
+func init() {
+	exportMrpackCmd.Flags().StringVarP(&exportOut, "output", "o", "", "output file (default <name>-<version>.mrpack)")
+	exportMrpackCmd.Flags().StringVar(&exportName, "name", "", "pack name (default: the manifest's folder name)")
+	exportMrpackCmd.Flags().StringVar(&exportVersion, "version", "1.0.0", "pack version")
+	exportMrpackCmd.Flags().StringVar(&exportLoaderVersion, "loader-version", "", "loader version to require (default: the manifest's)")
+	exportPackwizCmd.Flags().StringVar(&exportName, "name", "", "pack name (default: the manifest's folder name)")
+	exportPackwizCmd.Flags().StringVar(&exportVersion, "version", "1.0.0", "pack version")
+	exportListCmd.Flags().StringVar(&listFormat, "format", "md", "md, html or csv")
+	exportListCmd.Flags().StringVar(&listTemplate, "template", "", "render with your own text/template file instead")
+	exportListCmd.Flags().BoolVar(&listPrintTemplate, "print-template", false, "print the built-in template for --format and exit")
+	exportListCmd.Flags().BoolVar(&listRefresh, "refresh", false, "fetch project pages again instead of using the cache")
+	exportListCmd.Flags().StringVarP(&exportOut, "output", "o", "", "output file (default stdout)")
+	exportListCmd.Flags().StringVar(&exportName, "name", "", "heading (default: the modpack name or \"Modlist\")")
+	exportServerPackCmd.Flags().StringVarP(&exportOut, "output", "o", "", "output folder, or a .zip file (default server-pack)")
+	exportServerPackCmd.Flags().StringVar(&serverJVMArgs, "jvm-args", serverpack.DefaultJVMArgs, "JVM arguments written into the start scripts")
+	exportServerPackCmd.Flags().StringVar(&exportLoaderVersion, "loader-version", "", "loader version for the launcher (default: the manifest's)")
+	exportServerPackCmd.Flags().BoolVar(&serverNoLauncher, "no-launcher", false, "do not bundle the launcher, the start scripts download it on first run")
+	exportCmd.AddCommand(exportMrpackCmd, exportPackwizCmd, exportListCmd, exportServerPackCmd)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/packwiz"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create the manifest from another tool's pack",
+}
+
+var importPackwizCmd = &cobra.Command{
+	Use:   "packwiz <dir>",
+	Short: "Create the manifest from a packwiz pack, keeping version pins",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if side != manifest.SideClient && side != manifest.SideServer {
+			return fmt.Errorf("side must be %q or %q", manifest.SideClient, manifest.SideServer)
+		}
+		path := filepath.Join(gameDir, manifestRel)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+
+		pack, err := packwiz.Read(args[0])
+		if err != nil {
+			return err
+		}
+		m := manifest.New(path, pack.Minecraft())
+		if side == manifest.SideServer {
+			m.Side = side
+		}
+
+		rels := make([]string, 0, len(pack.Metafiles))
+		for rel := range pack.Metafiles {
+			rels = append(rels, rel)
+		}
+		sort.Strings(rels)
+		var added int
+		for _, rel := range rels {
+			ct, e, err := pack.Metafiles[rel].Entry(rel)
+			if err == nil {
+				err = m.AddEntry(ct, e)
+			}
+			if err != nil {
+				fmt.Printf("[-] %s skipped: %v\n", rel, err)
+				continue
+			}
+			added++
+		}
+		files, err := pack.CopyFiles(filepath.Join(m.Dir(), overrides.Common))
+		if err != nil {
+			return err
+		}
+		if err := m.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s from %s (%d entries, %d override files)\nRun 'mod install' to download everything.\n",
+			path, pack.Meta.Name, added, len(files))
+		return nil
+	},
+}
+
+var importModsDir string
+
+var importCurseForgeCmd = &cobra.Command{
+	Use:   "curseforge <pack.zip>",
+	Short: "Create the manifest from a CurseForge modpack export, moving files to Modrinth where possible",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if side != manifest.SideClient && side != manifest.SideServer {
+			return fmt.Errorf("side must be %q or %q", manifest.SideClient, manifest.SideServer)
+		}
+		path := filepath.Join(gameDir, manifestRel)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+
+		m := manifest.New(path, manifest.Minecraft{})
+		if side == manifest.SideServer {
+			m.Side = side
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		rep, err := inst.ImportCurseForge(cmd.Context(), args[0], importModsDir)
+		if err != nil {
+			return err
+		}
+
+		for _, pe := range rep.Matched {
+			fmt.Printf("[+] %-24s %s\n", pe.Entry.Slug, pe.Entry.VersionNumber)
+		}
+		for _, pe := range rep.Kept {
+			fmt.Printf("[+] %-24s %s (curseforge)\n", pe.Entry.Slug, pe.Entry.VersionNumber)
+		}
+		if len(rep.Unmatched) > 0 {
+			fmt.Println("\nNot found on Modrinth:")
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ITEM\tFROM\tTRY")
+			for _, u := range rep.Unmatched {
+				try := strings.Join(u.Suggestions, ", ")
+				if try == "" {
+					try = "-"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", u.Name, u.From, try)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("\nCreated %s from %s %s: %d on Modrinth, %d kept on CurseForge, %d unmatched, %d override files\n",
+			path, rep.Name, rep.Version, len(rep.Matched), len(rep.Kept), len(rep.Unmatched), len(rep.Overrides))
+		fmt.Println("Run 'mod install' to download everything.")
+		return nil
+	},
+}
+
+func init() {
+	importPackwizCmd.Flags().StringVar(&side, "side", manifest.SideClient, "install side (client, server)")
+	importCurseForgeCmd.Flags().StringVar(&side, "side", manifest.SideClient, "install side (client, server)")
+	importCurseForgeCmd.Flags().StringVar(&importModsDir, "mods", "", "folder with the pack's installed jars (e.g. the CurseForge instance's mods/)")
+	importCmd.AddCommand(importPackwizCmd, importCurseForgeCmd)
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/prism"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +18,8 @@ var (
 	fabric        bool
 	neoforge      bool
 	quilt         bool
+	side          string
+	initFrom      string
 )
 
 var initCmd = &cobra.Command{
@@ -29,10 +33,34 @@ var initCmd = &cobra.Command{
 		} else if len(args) == 0 {
 			dir = "."
 		}
+		var inst manifest.Minecraft
+		if game, ok := prism.GameDir(dir); ok {
+			var err error
+			if inst, err = prism.ReadMinecraft(dir); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(game, 0o755); err != nil {
+				return err
+			}
+			dir = game
+		}
+		if initFrom != "" {
+			return initFromPack(cmd, dir)
+		}
 		var mc manifest.Minecraft
 		mc.Loader = loader
 		mc.LoaderVersion = loaderVersion
 		mc.Version = mcVersion
+		// a Prism instance already knows its versions
+		if inst.Version != "" && mc.Version == "" {
+			mc.Version = inst.Version
+		}
+		if inst.Loader != "" && mc.Loader == "" && !forge && !fabric && !neoforge && !quilt {
+			mc.Loader = inst.Loader
+			if mc.LoaderVersion == "" {
+				mc.LoaderVersion = inst.LoaderVersion
+			}
+		}
 
 		count := 0
 		if forge {
@@ -82,8 +110,15 @@ var initCmd = &cobra.Command{
 			mc.LoaderVersion = "latest"
 		}
 
+		if side != manifest.SideClient && side != manifest.SideServer {
+			return fmt.Errorf("side must be %q or %q", manifest.SideClient, manifest.SideServer)
+		}
+
 		path := filepath.Join(dir, manifestRel)
 		m := manifest.New(path, mc)
+		if side == manifest.SideServer {
+			m.Side = side
+		}
 
 		if err := m.Save(); err != nil {
 			return err
@@ -95,10 +130,52 @@ var initCmd = &cobra.Command{
 
 func init() {
 	initCmd.Flags().StringVar(&mcVersion, "mc", "", "Minecraft version")
-	initCmd.Flags().StringVar(&loader, "loader", "", "loader (vanilla, fabric, quilt, neoforge...)")
+	initCmd.Flags().StringVar(&loader, "loader", "", "loader (vanilla, fabric, quilt, neoforge...) or plugin platform (paper, purpur, spigot, bukkit, folia, velocity, bungeecord)")
 	initCmd.Flags().StringVar(&loaderVersion, "loader-version", "", "loader version (optional, latest by default)")
 	initCmd.Flags().BoolVar(&forge, "forge", false, "use Forge loader")
 	initCmd.Flags().BoolVar(&fabric, "fabric", false, "use Fabric loader")
 	initCmd.Flags().BoolVar(&neoforge, "neoforge", false, "use NeoForge loader")
 	initCmd.Flags().BoolVar(&quilt, "quilt", false, "use Quilt loader")
+	initCmd.Flags().StringVar(&initFrom, "from", "", "create the instance from a .mrpack file or Modrinth modpack slug")
+	initCmd.Flags().StringVar(&side, "side", manifest.SideClient, "install side (client, server); server skips client-only projects")
+}
+
+// @brief initFromPack creates a manifest from a modpack and extracts its overrides.
+// @param cmd the init command
+// @param dir instance folder
+// @return error if the pack could not be read or mapped
+func initFromPack(cmd *cobra.Command, dir string) error {
+	if side != manifest.SideClient && side != manifest.SideServer {
+		return fmt.Errorf("side must be %q or %q", manifest.SideClient, manifest.SideServer)
+	}
+	path := filepath.Join(dir, manifestRel)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists, use 'mod modpack update --from' to switch packs", path)
+	}
+
+	m := manifest.New(path, manifest.Minecraft{Loader: loader})
+	if side == manifest.SideServer {
+		m.Side = side
+	}
+	inst, err := newInstaller(dir, m)
+	if err != nil {
+		return err
+	}
+	pack, err := inst.OpenPack(cmd.Context(), initFrom)
+	if err != nil {
+		return err
+	}
+	defer pack.Close()
+
+	entries, err := inst.PackEntries(cmd.Context(), &pack.Index)
+	if err != nil {
+		return err
+	}
+	changes := inst.PackDiff(entries)
+	if err := inst.ApplyPack(pack, changes); err != nil {
+		return err
+	}
+	printPackChanges(os.Stdout, changes)
+	fmt.Printf("Created %s from %s %s\nRun 'mod install' to download everything.\n", path, pack.Ref.Name, pack.Ref.VersionNumber)
+	return nil
 }
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/silask7188/ModrinthCLI/internal/jarmeta"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <file|slug>",
+	Short: "Show what a jar or pack says about itself (id, version, dependencies...)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, label := args[0], ""
+		if _, err := os.Stat(file); err != nil {
+			if _, err := os.Stat(filepath.Join(gameDir, file)); err == nil {
+				file = filepath.Join(gameDir, file)
+			} else {
+				m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+				if err != nil {
+					return fmt.Errorf("%s is neither a file nor a manifest entry: %w", args[0], err)
+				}
+				ct, e := m.Find(args[0])
+				if e == nil {
+					return fmt.Errorf("%s is neither a file nor a manifest entry", args[0])
+				}
+				if file = entryFile(ct, *e); file == "" {
+					return fmt.Errorf("%s is not installed, run 'mod install'", args[0])
+				}
+				label = fmt.Sprintf("%s %s", e.Slug, e.VersionNumber)
+			}
+		}
+
+		info, err := jarmeta.Read(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		row := func(k, v string) {
+			if v != "" {
+				fmt.Fprintf(tw, "%s\t%s\n", k, v)
+			}
+		}
+		row("File:", file)
+		row("Entry:", label)
+		row("Format:", info.Format)
+		row("ID:", info.ID)
+		row("Name:", info.Name)
+		row("Version:", info.Version)
+		if info.PackFormat != 0 {
+			row("Pack format:", fmt.Sprint(info.PackFormat))
+		}
+		row("Description:", strings.ReplaceAll(info.Description, "\n", " "))
+		row("License:", info.License)
+		row("Environment:", info.Environment)
+		row("Minecraft:", info.Minecraft)
+		row("Loader:", info.Loader)
+		row("Depends:", depList(info.Depends))
+		row("Breaks:", depList(info.Breaks))
+		row("Provides:", strings.Join(info.Provides, ", "))
+		bundled := make([]string, 0, len(info.Nested))
+		for _, n := range info.Nested {
+			bundled = append(bundled, n.ID+" "+n.Version)
+		}
+		row("Bundles:", strings.Join(bundled, ", "))
+		return tw.Flush()
+	},
+}
+
+// @brief entryFile finds the installed file of an entry, enabled or not.
+// Per-world entries return the first world that has a copy.
+// @param ct content type of the entry
+// @param e manifest entry
+// @return path, or "" if nothing is installed
+func entryFile(ct manifest.ContentType, e manifest.Entry) string {
+	if e.Filename == "" {
+		return ""
+	}
+	for _, dir := range manifest.Dirs(gameDir, ct, e) {
+		for _, name := range []string{e.Filename, e.Filename + ".disabled"} {
+			p := filepath.Join(dir, name)
+			if _, err := os.Stat(p); err == nil {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// @brief depList formats dependencies as "id range, id range".
+func depList(deps []jarmeta.Dep) string {
+	parts := make([]string, 0, len(deps))
+	for _, d := range deps {
+		parts = append(parts, d.String())
+	}
+	return strings.Join(parts, ", ")
+}
@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/silask7188/ModrinthCLI/internal/installer"
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
+var installFailFast bool
+
 var installCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Download / update everything that is enabled",
@@ -17,14 +25,82 @@ var installCmd = &cobra.Command{
 			println("Manifest not found. Create one with 'mod init --mc [version] --loader [loader]\n")
 			return err
 		}
-		inst, err := installer.New(gameDir, m)
+		inst, err := newInstaller(gameDir, m)
 		if err != nil {
 			return err
 		}
-		if err := inst.Install(cmd.Context()); err != nil {
-			return err
-		}
-		// fmt.Println("All done!")
-		return nil
+		return runInstall(cmd.Context(), inst, installFailFast)
 	},
 }
+
+// @brief runInstall installs every enabled entry. Best-effort installs end
+// with a summary; fail-fast ones return the first error.
+// @param ctx context for cancellation
+// @param inst installer of the game dir
+// @param failFast stop at the first failed entry
+// @return error if the install stopped or any entry failed
+func runInstall(ctx context.Context, inst *installer.Installer, failFast bool) error {
+	rep, err := inst.Install(ctx, failFast)
+	if err != nil || failFast {
+		return err
+	}
+	return summarize(rep)
+}
+
+// @brief textOut returns where human-readable output goes: stderr with
+// --progress json, so stdout stays JSON lines.
+func textOut() io.Writer {
+	if progress == "json" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// @brief summarize prints what happened to each entry that needed work,
+// then the totals. With --progress json it goes to stderr so stdout stays
+// JSON lines.
+// @param rep install report
+// @return error if any entry failed, for a non-zero exit code
+func summarize(rep *installer.Report) error {
+	out := textOut()
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	rows := 0
+	for _, r := range rep.Results {
+		if r.Outcome == installer.Present || r.Outcome == installer.Disabled {
+			continue
+		}
+		if rows == 0 {
+			fmt.Fprintln(tw, "\nITEM\tTYPE\tRESULT\tVERSION\tDETAIL")
+		}
+		rows++
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Slug, r.Type, r.Outcome, dash(r.Version), dash(r.Reason))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var parts []string
+	for _, o := range []installer.Outcome{installer.Installed, installer.Present, installer.Healed, installer.Skipped, installer.Disabled, installer.Failed} {
+		if n := rep.Count(o); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, o))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(out, "\n%s\n", strings.Join(parts, ", "))
+	}
+	if n := rep.Count(installer.Failed); n > 0 {
+		return fmt.Errorf("%d of %d entries failed", n, len(rep.Results))
+	}
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	installCmd.Flags().BoolVar(&installFailFast, "fail-fast", false, "stop at the first failed entry instead of trying them all")
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/silask7188/ModrinthCLI/internal/installer"
+	"github.com/silask7188/ModrinthCLI/internal/license"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var licensesRefresh bool
+
+var licensesCmd = &cobra.Command{
+	Use:   "licenses",
+	Short: "List every entry's license and flag the ones that may not be redistributable",
+	Long: `List every entry's license and flag proprietary, unknown and custom
+licenses. With a ` + license.PolicyFile + ` next to project.json, entries the
+policy does not allow are flagged too, and 'mod add', 'mod export mrpack' and
+'mod export server-pack' warn about them (or refuse, with "on_violation": "fail").`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		prjs, err := inst.Projects(cmd.Context(), licensesRefresh)
+		if err != nil {
+			return err
+		}
+		pol, err := license.LoadPolicy(m.Dir())
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ITEM\tLICENSE\tNAME\tFLAG")
+		for _, ct := range manifest.ContentTypes() {
+			for _, e := range m.Entries(ct) {
+				p := prjs[e.Slug]
+				id, name := p.License, p.LicenseName
+				if id == "" {
+					id = "-"
+				}
+				if name == "" {
+					name = "-"
+				}
+				flag := license.Classify(p.License)
+				if reason := pol.Check(e.Slug, p.License); reason != "" {
+					flag = reason
+				}
+				if flag == "" {
+					flag = "-"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Slug, id, name, flag)
+			}
+		}
+		return tw.Flush()
+	},
+}
+
+// @brief checkLicenses prints the entries the license policy does not allow.
+// @param ctx context for cancellation
+// @param inst installer for the pack
+// @param slugs entries to check
+// @return error if there were violations and the policy says to fail
+func checkLicenses(ctx context.Context, inst *installer.Installer, slugs []string) error {
+	pol, bad, err := inst.LicenseViolations(ctx, slugs)
+	if err != nil {
+		return fmt.Errorf("license check failed: %w", err)
+	}
+	for _, b := range bad {
+		fmt.Printf("[!] license: %s\n", b)
+	}
+	if len(bad) > 0 && pol.Fail() {
+		return fmt.Errorf("%d entries violate %s", len(bad), license.PolicyFile)
+	}
+	return nil
+}
+
+// @brief enabledSlugs lists the enabled entries of every content type.
+func enabledSlugs(m *manifest.Manifest) []string {
+	var out []string
+	for _, ct := range manifest.ContentTypes() {
+		for _, e := range m.Entries(ct) {
+			if e.Enable {
+				out = append(out, e.Slug)
+			}
+		}
+	}
+	return out
+}
+
+func init() {
+	licensesCmd.Flags().BoolVar(&licensesRefresh, "refresh", false, "fetch project pages again instead of using the cache")
+}
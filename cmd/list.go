@@ -5,10 +5,13 @@ import (
 	"path/filepath"
 	"text/tabwriter"
 
+	"github.com/silask7188/ModrinthCLI/internal/jarmeta"
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
+var listVerbose bool
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Show manifest entries",
@@ -18,23 +21,37 @@ var listCmd = &cobra.Command{
 			return err
 		}
 		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-		// println("MODS")
-		fmt.Fprintln(tw, "\tITEM\tVERSION\tTYPE")
-		for _, e := range m.Mods {
-			en := "✓"
-			if !e.Enable {
-				en = "x"
-			}
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", en, e.Slug, e.VersionNumber, e.Dest)
-		}
-		// println("RESOURCE PACKS")
-		for _, e := range m.ResourcePacks {
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", "", e.Slug, e.VersionNumber, e.Dest)
+		if listVerbose {
+			fmt.Fprintln(tw, "\tITEM\tVERSION\tTYPE\tSIDE\tJAR ID\tJAR VERSION")
+		} else {
+			fmt.Fprintln(tw, "\tITEM\tVERSION\tTYPE\tSIDE")
 		}
-		// println("SHADERS")
-		for _, e := range m.Shaders {
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", "", e.Slug, e.VersionNumber, e.Dest)
+		for _, ct := range manifest.ContentTypes() {
+			for _, e := range m.Entries(ct) {
+				en := "✓"
+				if !e.Enable {
+					en = "x"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s", en, e.Slug, e.VersionNumber, e.Dest, e.SideLabel())
+				if listVerbose {
+					id, ver := "-", "-"
+					if f := entryFile(ct, e); f != "" {
+						if info, err := jarmeta.Read(f); err == nil {
+							id, ver = info.ID, info.Version
+							if info.Format == "pack" {
+								id, ver = "(pack)", fmt.Sprintf("format %d", info.PackFormat)
+							}
+						}
+					}
+					fmt.Fprintf(tw, "\t%s\t%s", id, ver)
+				}
+				fmt.Fprintln(tw)
+			}
 		}
 		return tw.Flush()
 	},
 }
+
+func init() {
+	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "also show the mod id and version read from each installed jar")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/silask7188/ModrinthCLI/internal/installer"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	packFrom     string
+	packDryRun   bool
+	packFailFast bool
+)
+
+var modpackCmd = &cobra.Command{
+	Use:   "modpack",
+	Short: "Manage the modpack this instance was created from",
+}
+
+var modpackUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Move to the newest version of the modpack, showing what changes first",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		from := packFrom
+		if from == "" {
+			switch {
+			case m.Modpack == nil:
+				return fmt.Errorf("this instance was not created from a modpack, use 'mod init --from'")
+			case m.Modpack.Slug == "":
+				return fmt.Errorf("%s was imported from a file, pass the new one with --from", m.Modpack.Name)
+			}
+			from = m.Modpack.Slug
+		}
+
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		pack, err := inst.OpenPack(cmd.Context(), from)
+		if err != nil {
+			return err
+		}
+		defer pack.Close()
+
+		out := textOut()
+		if m.Modpack != nil && packFrom == "" && m.Modpack.Version == pack.Ref.Version {
+			fmt.Fprintf(out, "%s is up-to-date (%s) ✓\n", m.Modpack.Name, m.Modpack.VersionNumber)
+			return nil
+		}
+		entries, err := inst.PackEntries(cmd.Context(), &pack.Index)
+		if err != nil {
+			return err
+		}
+		changes := inst.PackDiff(entries)
+
+		cur := "none"
+		if m.Modpack != nil {
+			cur = m.Modpack.VersionNumber
+		}
+		fmt.Fprintf(out, "%s %s -> %s\n", pack.Ref.Name, cur, pack.Ref.VersionNumber)
+		mc := installer.PackMinecraft(m.Minecraft, &pack.Index)
+		if mc.Version != m.Minecraft.Version {
+			fmt.Fprintf(out, "    minecraft %s -> %s\n", m.Minecraft.Version, mc.Version)
+		}
+		if mc.Loader != m.Minecraft.Loader || mc.LoaderVersion != m.Minecraft.LoaderVersion {
+			fmt.Fprintf(out, "    %s %s -> %s %s\n", m.Minecraft.Loader, m.Minecraft.LoaderVersion, mc.Loader, mc.LoaderVersion)
+		}
+		printPackChanges(out, changes)
+		if packDryRun {
+			return nil
+		}
+
+		if err := inst.ApplyPack(pack, changes); err != nil {
+			return err
+		}
+		return runInstall(cmd.Context(), inst, packFailFast)
+	},
+}
+
+// @brief printPackChanges lists what a modpack import or update does to the manifest.
+// @param out where to print
+// @param changes result of Installer.PackDiff
+func printPackChanges(out io.Writer, changes []installer.PackChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No content changes")
+		return
+	}
+	var added, removed, changed int
+	for _, c := range changes {
+		switch {
+		case c.Old == nil:
+			fmt.Fprintf(out, "[+] %-24s %s\n", c.New.Slug, c.New.VersionNumber)
+			added++
+		case c.New == nil:
+			fmt.Fprintf(out, "[-] %-24s %s\n", c.Old.Slug, c.Old.VersionNumber)
+			removed++
+		default:
+			fmt.Fprintf(out, "[~] %-24s %s -> %s\n", c.New.Slug, c.Old.VersionNumber, c.New.VersionNumber)
+			changed++
+		}
+	}
+	fmt.Fprintf(out, "%d added, %d removed, %d changed\n", added, removed, changed)
+}
+
+func init() {
+	modpackUpdateCmd.Flags().StringVar(&packFrom, "from", "", "update from this .mrpack file (or modpack slug) instead")
+	modpackUpdateCmd.Flags().BoolVar(&packDryRun, "dry-run", false, "show the changes without applying them")
+	modpackUpdateCmd.Flags().BoolVar(&packFailFast, "fail-fast", false, "stop at the first failed entry instead of trying them all")
+	modpackCmd.AddCommand(modpackUpdateCmd)
+}
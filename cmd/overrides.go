@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overridesForce bool
+	captureSide    string
+)
+
+var overridesCmd = &cobra.Command{
+	Use:   "overrides",
+	Short: "Manage config files shipped with the pack (overrides/ next to the manifest)",
+}
+
+var overridesApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Copy override files into the game dir without reinstalling",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+		inst, err := newInstaller(gameDir, m)
+		if err != nil {
+			return err
+		}
+		return inst.ApplyOverrides(overridesForce)
+	},
+}
+
+var overridesCaptureCmd = &cobra.Command{
+	Use:   "capture <path> [...]",
+	Short: "Copy live files or folders from the game dir back into the pack's overrides",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+		if err != nil {
+			return err
+		}
+
+		folder := overrides.Common
+		switch captureSide {
+		case "":
+		case manifest.SideClient:
+			folder = overrides.Client
+		case manifest.SideServer:
+			folder = overrides.Server
+		default:
+			return fmt.Errorf("side must be %q or %q", manifest.SideClient, manifest.SideServer)
+		}
+		dir := filepath.Join(m.Dir(), folder)
+
+		for _, path := range args {
+			captured, err := overrides.Capture(dir, gameDir, path)
+			if err != nil {
+				return fmt.Errorf("failed to capture %s: %w", path, err)
+			}
+			for _, rel := range captured {
+				fmt.Fprintf(cmd.OutOrStdout(), "captured %s -> %s\n", rel, folder)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	overridesApplyCmd.Flags().BoolVar(&overridesForce, "force", false, "replace files that were edited locally")
+	overridesCaptureCmd.Flags().StringVar(&captureSide, "side", "", "capture into client-overrides or server-overrides instead of overrides")
+	overridesCmd.AddCommand(overridesApplyCmd, overridesCaptureCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/prism"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prismInstances     string
+	prismLoaderVersion string
+	prismNoInstall     bool
+	prismFailFast      bool
+)
+
+var prismCmd = &cobra.Command{
+	Use:   "prism",
+	Short: "Work with Prism Launcher / MultiMC instances",
+}
+
+var prismCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a Prism instance from the manifest and install its content",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src := filepath.Join(gameDir, manifestRel)
+		m, err := manifest.Load(src)
+		if err != nil {
+			return err
+		}
+		if m.TargetSide() == manifest.SideServer {
+			return fmt.Errorf("this is a server manifest, prism instances are client-side")
+		}
+		mc := m.Minecraft
+		if prismLoaderVersion != "" {
+			mc.LoaderVersion = prismLoaderVersion
+		}
+
+		root := prismInstances
+		if root == "" {
+			if root, err = prism.InstancesDir(); err != nil {
+				return err
+			}
+		}
+		dir := filepath.Join(root, args[0])
+		game, err := prism.Create(dir, args[0], mc)
+		if err != nil {
+			return fmt.Errorf("failed to create instance: %w", err)
+		}
+
+		// the instance gets its own copy of the pack
+		b, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(game, manifestRel)
+		if err := os.WriteFile(dst, b, 0o644); err != nil {
+			return err
+		}
+		if err := overrides.Copy(m.Dir(), game); err != nil {
+			return fmt.Errorf("failed to copy overrides: %w", err)
+		}
+		if m, err = manifest.Load(dst); err != nil {
+			return err
+		}
+		if m.Minecraft != mc {
+			m.Minecraft = mc
+			if err := m.Save(); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(textOut(), "Created instance %s\n", dir)
+		if prismNoInstall {
+			return nil
+		}
+
+		inst, err := newInstaller(game, m)
+		if err != nil {
+			return err
+		}
+		return runInstall(cmd.Context(), inst, prismFailFast)
+	},
+}
+
+func init() {
+	prismCreateCmd.Flags().StringVar(&prismInstances, "instances", "", "Prism's instances folder (default: the launcher's data folder)")
+	prismCreateCmd.Flags().StringVar(&prismLoaderVersion, "loader-version", "", "loader version for the instance (required if the manifest says latest)")
+	prismCreateCmd.Flags().BoolVar(&prismNoInstall, "no-install", false, "only write the instance, do not download content")
+	prismCreateCmd.Flags().BoolVar(&prismFailFast, "fail-fast", false, "stop at the first failed entry instead of trying them all")
+	prismCmd.AddCommand(prismCreateCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/silask7188/ModrinthCLI/internal/status"
+	"github.com/spf13/cobra"
+)
+
+var pruneDryRun bool
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Move untracked files out of the content folders into a quarantine",
+	Long: `Move untracked files (see 'mod status') out of the content folders into
+.modcli/quarantine/<time>/ in the game dir. Files matched by .modignore next to
+the manifest are kept. 'mod prune restore' puts a batch back.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		folders, err := scanStatus()
+		if err != nil {
+			return err
+		}
+		files := status.Paths(folders, status.Untracked)
+		if len(files) == 0 {
+			fmt.Println("No untracked files")
+			return nil
+		}
+		if pruneDryRun {
+			for _, rel := range files {
+				fmt.Printf("[-] %s would be quarantined\n", rel)
+			}
+			return nil
+		}
+
+		b, err := status.Prune(gameDir, files)
+		for _, rel := range b.Files {
+			fmt.Printf("[-] %s\n", rel)
+		}
+		if err != nil {
+			return err
+		}
+		dir, _ := filepath.Rel(gameDir, filepath.Join(status.QuarantineDir(gameDir), b.ID))
+		fmt.Printf("Moved %d files to %s, undo with 'mod prune restore %s'\n", len(b.Files), dir, b.ID)
+		return nil
+	},
+}
+
+var pruneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List quarantined files by prune",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		batches, err := status.Batches(gameDir)
+		if err != nil {
+			return err
+		}
+		if len(batches) == 0 {
+			fmt.Println("Quarantine is empty")
+			return nil
+		}
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BATCH\tFILE")
+		for _, b := range batches {
+			for _, rel := range b.Files {
+				fmt.Fprintf(tw, "%s\t%s\n", b.ID, rel)
+			}
+		}
+		return tw.Flush()
+	},
+}
+
+var pruneRestoreCmd = &cobra.Command{
+	Use:   "restore [BATCH]",
+	Short: "Put quarantined files back (the latest prune by default)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batches, err := status.Batches(gameDir)
+		if err != nil {
+			return err
+		}
+		if len(batches) == 0 {
+			return fmt.Errorf("quarantine is empty")
+		}
+		b := batches[len(batches)-1]
+		if len(args) == 1 {
+			found := false
+			for _, c := range batches {
+				if c.ID == args[0] {
+					b, found = c, true
+				}
+			}
+			if !found {
+				return fmt.Errorf("no quarantine batch %q, see 'mod prune list'", args[0])
+			}
+		}
+
+		restored, kept, err := status.Restore(gameDir, b)
+		for _, rel := range restored {
+			fmt.Printf("[+] %s\n", rel)
+		}
+		for _, rel := range kept {
+			fmt.Printf("[!] %s exists again, left in quarantine\n", rel)
+		}
+		return err
+	},
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "only list what would be moved")
+	pruneCmd.AddCommand(pruneListCmd, pruneRestoreCmd)
+}
@@ -1,17 +1,27 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/silask7188/ModrinthCLI/internal/events"
+	"github.com/silask7188/ModrinthCLI/internal/installer"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/prism"
 	"github.com/spf13/cobra"
 )
 
 var (
 	gameDir     string
 	manifestRel string
+	progress    string
 	rootCmd     = &cobra.Command{
 		Use:   "mod",
 		Short: "Minecraft Mod/Resourcepack/Shader Manager",
+		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+			useInstance()
+		},
 	}
 )
 
@@ -19,11 +29,61 @@ func Execute() {
 	// global flags
 	rootCmd.PersistentFlags().StringVar(&gameDir, "dir", ".", "path to project directory")
 	rootCmd.PersistentFlags().StringVar(&manifestRel, "manifest", "project.json", "manifest filename")
+	rootCmd.PersistentFlags().StringVar(&progress, "progress", "auto", "how installs report progress: auto, tty, plain or json (JSON lines)")
 
 	// subcommands
-	rootCmd.AddCommand(initCmd, addCmd, listCmd, installCmd, updateCmd, enableCmd, disableCmd, removeCmd, searchCmd, checkCmd)
+	rootCmd.AddCommand(initCmd, addCmd, listCmd, installCmd, updateCmd, enableCmd, disableCmd, removeCmd, searchCmd, checkCmd, overridesCmd, exportCmd, importCmd, modpackCmd, prismCmd, inspectCmd, licensesCmd, cacheCmd, statusCmd, pruneCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
+
+// @brief newInstaller creates an installer reporting the way --progress asks.
+// @param dir game directory
+// @param m manifest
+// @return installer or error
+func newInstaller(dir string, m *manifest.Manifest) (*installer.Installer, error) {
+	inst, err := installer.New(dir, m)
+	if err != nil {
+		return nil, err
+	}
+	switch progress {
+	case "tty":
+		inst.SetEvents(events.NewTTY(os.Stdout))
+	case "json":
+		inst.SetEvents(events.NewJSON(os.Stdout))
+	case "plain":
+	case "auto", "":
+		if events.IsTerminal(os.Stdout) {
+			inst.SetEvents(events.NewTTY(os.Stdout))
+		}
+	default:
+		return nil, fmt.Errorf("--progress must be auto, tty, plain or json")
+	}
+	return inst, nil
+}
+
+// @brief useInstance points gameDir into a Prism/MultiMC instance's game folder
+// when --dir names the instance root, and warns if mmc-pack.json disagrees
+// with the manifest. Warnings go to stderr, stdout may be JSON lines.
+func useInstance() {
+	game, ok := prism.GameDir(gameDir)
+	if !ok {
+		return
+	}
+	inst := gameDir
+	gameDir = game
+	m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+	if err != nil {
+		return
+	}
+	mc, err := prism.ReadMinecraft(inst)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[!] %s: %v\n", inst, err)
+		return
+	}
+	for _, msg := range prism.Mismatches(mc, m.Minecraft) {
+		fmt.Fprintf(os.Stderr, "[!] %s\n", msg)
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
-	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/source"
 	"github.com/spf13/cobra"
 )
 
@@ -15,13 +15,15 @@ var (
 	includeMods          bool
 	includeResourcePacks bool
 	includeShaders       bool
+	includeDatapacks     bool
+	includePlugins       bool
+	searchCurseForge     bool
 	limit                int
-	offset               int = 1
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search <query>",
-	Short: "Search for mods, resource packs, or shaders on Modrinth. By default, searches for all",
+	Short: "Search for mods, plugins, resource packs, shaders or datapacks on Modrinth or CurseForge. By default, searches for all",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			return fmt.Errorf("no search query provided")
@@ -33,9 +35,6 @@ var searchCmd = &cobra.Command{
 			return fmt.Errorf("failed to load manifest: %w", err)
 		}
 
-		var facets modrinth.Facets
-		facets.MinecraftVersion = m.Minecraft.Version
-
 		// Append all specified project types
 		var types []string
 		if includeMods {
@@ -47,64 +46,68 @@ var searchCmd = &cobra.Command{
 		if includeShaders {
 			types = append(types, "shader")
 		}
-		if len(types) > 0 {
-			facets.ProjectType = types
+		if includeDatapacks {
+			types = append(types, "datapack")
 		}
-
-		params := modrinth.SearchParams{
-			Query:  query,
-			Facets: facets,
-			Offset: offset - 1,
-			Limit:  limit,
+		if includePlugins && !includeMods {
+			types = append(types, "mod") // Modrinth lists plugins as mods
 		}
 
-		client, err := modrinth.New("https://api.modrinth.com/v2/")
+		name := source.Modrinth
+		if searchCurseForge {
+			name = source.CurseForge
+		}
+		p, err := openProvider(name)
 		if err != nil {
-			return fmt.Errorf("failed to create Modrinth client: %w", err)
+			return err
 		}
 
-		result, err := client.Search(cmd.Context(), params)
+		hits, err := p.SearchProjects(cmd.Context(), query, source.Filter{
+			GameVersion:  m.Minecraft.Version,
+			ProjectTypes: types,
+		}, limit)
 		if err != nil {
-			return fmt.Errorf("failed to search Modrinth: %w", err)
+			return fmt.Errorf("failed to search %s: %w", name, err)
 		}
 
-		if len(result.Hits) == 0 {
+		if len(hits) == 0 {
 			return fmt.Errorf("no results found for '%s'", query)
 		}
 
-		// Group results
-		var mods, packs, shaders []modrinth.Project
-		for _, hit := range result.Hits {
-			switch hit.ProjectType {
-			case "mod":
-				if contains(hit.Categories, m.Minecraft.Loader) && contains(hit.Versions, m.Minecraft.Version) {
-					mods = append(mods, hit)
+		// Group results by content type, keeping only what fits this instance
+		groups := make(map[string][]source.Project)
+		for _, hit := range hits {
+			ct, ok := manifest.TypeOf(&hit, m.Minecraft)
+			if !ok {
+				continue
+			}
+			// providers that report loaders and versions per hit get filtered here
+			if loaders := ct.Loaders(m.Minecraft); len(loaders) > 0 {
+				if len(hit.Loaders) > 0 && !containsAny(hit.Loaders, loaders) {
+					continue
+				}
+				if len(hit.GameVersions) > 0 && !contains(hit.GameVersions, m.Minecraft.Version) {
+					continue
 				}
-			case "resourcepack":
-				packs = append(packs, hit)
-			case "shader":
-				shaders = append(shaders, hit)
 			}
+			groups[ct.Name] = append(groups[ct.Name], hit)
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-		if len(mods) > 0 {
-			fmt.Fprintln(w, "MODS")
-			for _, p := range mods {
-				fmt.Fprintf(w, "%s\t%s\thttps://modrinth.com/mod/%s\n", p.Slug, p.Title, p.Slug)
+		first := true
+		for _, ct := range manifest.ContentTypes() {
+			group := groups[ct.Name]
+			if len(group) == 0 {
+				continue
 			}
-		}
-		if len(packs) > 0 {
-			fmt.Fprintln(w, "\nRESOURCE PACKS")
-			for _, p := range packs {
-				fmt.Fprintf(w, "%s\t%s\thttps://modrinth.com/resourcepack/%s\n", p.Slug, p.Title, p.Slug)
+			if !first {
+				fmt.Fprintln(w)
 			}
-		}
-		if len(shaders) > 0 {
-			fmt.Fprintln(w, "\nSHADERS")
-			for _, p := range shaders {
-				fmt.Fprintf(w, "%s\t%s\thttps://modrinth.com/shader/%s\n", p.Slug, p.Title, p.Slug)
+			first = false
+			fmt.Fprintln(w, ct.Label)
+			for _, hit := range group {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", hit.Slug, hit.Title, hit.URL)
 			}
 		}
 		w.Flush()
@@ -116,8 +119,19 @@ func init() {
 	searchCmd.Flags().BoolVarP(&includeMods, "mod", "m", false, "Include mods in the search")
 	searchCmd.Flags().BoolVarP(&includeResourcePacks, "resourcepack", "r", false, "Include resource packs in the search")
 	searchCmd.Flags().BoolVarP(&includeShaders, "shaders", "s", false, "Include shaders in the search")
+	searchCmd.Flags().BoolVarP(&includeDatapacks, "datapack", "d", false, "Include datapacks in the search")
+	searchCmd.Flags().BoolVarP(&includePlugins, "plugin", "p", false, "Include server plugins in the search")
 	searchCmd.Flags().IntVarP(&limit, "limit", "l", 30, "Number of results to return (default 30)")
-	// searchCmd.Flags().IntVarP(&offset, "page", "p", 1, "Page (default 1)")
+	searchCmd.Flags().BoolVar(&searchCurseForge, "curseforge", false, "Search CurseForge instead of Modrinth (needs an API key)")
+}
+
+func containsAny(slice []string, items []string) bool {
+	for _, item := range items {
+		if contains(slice, item) {
+			return true
+		}
+	}
+	return false
 }
 
 func contains(slice []string, item string) bool {
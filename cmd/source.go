@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/config"
+	"github.com/silask7188/ModrinthCLI/internal/curseforge"
+	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// @brief openProvider creates the client for a provider name.
+// @param name source.Modrinth or source.CurseForge
+// @return provider or error if unknown or not configured
+func openProvider(name string) (source.Provider, error) {
+	switch name {
+	case source.Modrinth:
+		return modrinth.New("https://api.modrinth.com/v2/")
+	case source.CurseForge:
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return curseforge.FromConfig(cfg)
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
+
+// @brief splitProvider splits a "cf:slug" argument into provider and id.
+// @param arg slug, id or URL, optionally prefixed with cf: / curseforge:
+// @return provider name and the remaining id
+func splitProvider(arg string) (string, string) {
+	for _, prefix := range []string{"cf:", "curseforge:"} {
+		if rest, ok := strings.CutPrefix(arg, prefix); ok {
+			return source.CurseForge, rest
+		}
+	}
+	return source.Modrinth, modrinth.ParseSlug(arg)
+}
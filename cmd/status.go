@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/status"
+	"github.com/spf13/cobra"
+)
+
+var statusAll bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show how the files in each content folder compare with the manifest",
+	Long: `Show how the files in each content folder compare with the manifest:
+modified, missing and disabled entry files, and untracked files the manifest
+knows nothing about. Unchanged files are only counted unless --all is given.
+Untracked files matched by .modignore next to the manifest are listed as
+ignored; 'mod prune' moves the other untracked files out of the way.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		folders, err := scanStatus()
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		totals := map[status.State]int{}
+		for _, f := range folders {
+			var shown []status.File
+			for _, file := range f.Files {
+				totals[file.State]++
+				if statusAll || file.State != status.Tracked {
+					shown = append(shown, file)
+				}
+			}
+			if len(shown) == 0 {
+				continue
+			}
+			fmt.Fprintf(out, "%s/\n", f.Dir)
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			for _, file := range shown {
+				owner := file.Owner
+				if owner == "" || owner == file.Name {
+					owner = "-"
+				}
+				fmt.Fprintf(tw, "  %s\t%s\t%s\n", file.State, file.Name, owner)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+
+		states := []status.State{status.Tracked, status.Modified, status.Missing, status.Disabled, status.Untracked, status.Ignored}
+		parts := make([]string, len(states))
+		for i, s := range states {
+			parts[i] = fmt.Sprintf("%d %s", totals[s], s)
+		}
+		fmt.Fprintln(out, strings.Join(parts, ", "))
+		return nil
+	},
+}
+
+// @brief scanStatus loads the manifest and ignore file and scans the game dir.
+// @return folders, or error
+func scanStatus() ([]status.Folder, error) {
+	m, err := manifest.Load(filepath.Join(gameDir, manifestRel))
+	if err != nil {
+		return nil, err
+	}
+	ign, err := status.LoadIgnore(m.Dir())
+	if err != nil {
+		return nil, err
+	}
+	folders, err := status.Scan(gameDir, m, ign)
+	if err != nil {
+		return nil, err
+	}
+	return folders, m.Hashes().Save()
+}
+
+func init() {
+	statusCmd.Flags().BoolVarP(&statusAll, "all", "a", false, "also list unchanged tracked files")
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/silask7188/ModrinthCLI/internal/installer"
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
 
 	"github.com/spf13/cobra"
 )
 
-var dryRun bool
+var (
+	dryRun         bool
+	updateFailFast bool
+)
 
 var updateCmd = &cobra.Command{
 	Use:   "update",
@@ -22,48 +24,62 @@ var updateCmd = &cobra.Command{
 		}
 
 		print("Checking for mods not yet installed...\n")
-		inst, err := installer.New(gameDir, m)
+		inst, err := newInstaller(gameDir, m)
 		if err != nil {
 			return fmt.Errorf("failed to create installer: %w", err)
 		}
-		if err := inst.Install(cmd.Context()); err != nil {
+		rep, err := inst.Install(cmd.Context(), updateFailFast)
+		if err != nil {
 			return err
 		}
+		done := func() error {
+			if updateFailFast {
+				return nil
+			}
+			return summarize(rep)
+		}
 
-		plan, err := inst.PlanUpdates(cmd.Context())
+		plan, err := inst.PlanUpdates(cmd.Context(), updateFailFast)
 		if err != nil {
 			return err
 		}
+		out := textOut()
 		if len(plan) == 0 {
-			fmt.Println("Everything is up-to-date ✓")
-			return nil
+			fmt.Fprintln(out, "Everything is up-to-date ✓")
+			return done()
 		}
 		print("Checking for updates...\n")
 		var total int
 		for _, p := range plan {
-			if p.CurrentVersion == "" {
-				fmt.Printf("[ ] %-20s  %s -> %s (new)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
+			if p.Err != nil {
+				fmt.Fprintf(out, "[x] %-20s  %s (%v)\n", p.Entry.Slug, p.CurrentVersion, p.Err)
+			} else if p.CurrentVersion == "" {
+				fmt.Fprintf(out, "[ ] %-20s  %s -> %s (new)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
 				total++
 			} else if p.CurrentVersion == p.TargetVersion {
-				fmt.Printf("[=] %-20s  %s -> %s (already up-to-date)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
+				fmt.Fprintf(out, "[=] %-20s  %s -> %s (already up-to-date)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
 			} else if p.TargetVersion == "" {
-				fmt.Printf("[x] %-20s  %s -> %s (no compatible version found)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
+				fmt.Fprintf(out, "[x] %-20s  %s -> %s (no compatible version found)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
 			} else if p.TargetVersion == "latest" {
-				fmt.Printf("[ ] %-20s  %s -> %s (latest)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
+				fmt.Fprintf(out, "[ ] %-20s  %s -> %s (latest)\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
 				total++
 			} else {
-				fmt.Printf("[ ] %-20s  %s -> %s\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
+				fmt.Fprintf(out, "[ ] %-20s  %s -> %s\n", p.Entry.Slug, p.CurrentVersion, p.TargetVersion)
 				total++
 			}
 		}
-		fmt.Printf("Found %d updates\n", total)
-		if dryRun {
-			return nil
+		fmt.Fprintf(out, "Found %d updates\n", total)
+		if dryRun || total == 0 {
+			return done()
+		}
+		if rep, err = inst.Install(cmd.Context(), updateFailFast); err != nil {
+			return err
 		}
-		return inst.Install(cmd.Context())
+		return done()
 	},
 }
 
 func init() {
 	updateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show updates without installing")
+	updateCmd.Flags().BoolVar(&updateFailFast, "fail-fast", false, "stop at the first failed entry instead of trying them all")
 }
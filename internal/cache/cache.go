@@ -0,0 +1,435 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/config"
+	"github.com/silask7188/ModrinthCLI/internal/fsutil"
+)
+
+// Cache is a download cache shared by every game dir of the user.
+// Files live under sha1/<2>/<sha1>; sha512/<2>/<sha512> holds the sha1 of the
+// same file so releases that only carry a SHA512 find it too.
+// A nil *Cache is a disabled cache: lookups miss and nothing is stored.
+type Cache struct {
+	dir string
+	mu  sync.Mutex // guards manifests.json within one process
+}
+
+const (
+	sha1Dir      = "sha1"
+	sha512Dir    = "sha512"
+	partialDir   = "partial" // resumable downloads, see Partial
+	manifestList = "manifests.json"
+)
+
+// @brief DefaultDir returns where the cache lives unless the config says otherwise.
+// @return <user cache dir>/modrinth-cli/artifacts, or error if there is no user cache dir
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "modrinth-cli", "artifacts"), nil
+}
+
+// @brief Open opens (and creates) a cache folder.
+// @param dir cache folder
+// @return cache or error if the folder cannot be created
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// @brief FromConfig opens the cache the user's config points at.
+// @param cfg loaded config
+// @return cache, nil if the cache is disabled, or error
+func FromConfig(cfg *config.Config) (*Cache, error) {
+	if cfg.Cache.Disabled {
+		return nil, nil
+	}
+	dir := cfg.Cache.Dir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return Open(dir)
+}
+
+// @brief Dir returns the cache folder.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) blob(sha1 string) string {
+	return filepath.Join(c.dir, sha1Dir, sha1[:2], sha1)
+}
+
+func (c *Cache) pointer(sha512 string) string {
+	return filepath.Join(c.dir, sha512Dir, sha512[:2], sha512)
+}
+
+// @brief Lookup finds a cached file by hash. The file is hashed again and
+// dropped from the cache if it no longer matches the SHA1 it is stored under.
+// @param want expected hashes, either may be empty
+// @return path of the cached file, its hashes, and whether it was found
+func (c *Cache) Lookup(want checksum.Sums) (string, checksum.Sums, bool) {
+	if c == nil {
+		return "", checksum.Sums{}, false
+	}
+	sha1 := strings.ToLower(want.SHA1)
+	if sha1 == "" {
+		sha1 = c.resolve(want.SHA512)
+	}
+	if !checksum.Valid(checksum.SHA1, sha1) {
+		return "", checksum.Sums{}, false
+	}
+	p := c.blob(sha1)
+	got, err := checksum.File(p)
+	if err != nil {
+		return "", checksum.Sums{}, false
+	}
+	if got.SHA1 != sha1 {
+		os.Remove(p) // corrupt
+		return "", checksum.Sums{}, false
+	}
+	if want.Check(got) != nil {
+		return "", checksum.Sums{}, false
+	}
+	return p, got, true
+}
+
+// resolve follows a sha512 pointer to the SHA1 the file is stored under.
+// Anything but a well-formed digest resolves to "", so no caller builds a
+// path from it.
+func (c *Cache) resolve(sha512 string) string {
+	sha512 = strings.ToLower(sha512)
+	if !checksum.Valid(checksum.SHA512, sha512) {
+		return ""
+	}
+	b, err := os.ReadFile(c.pointer(sha512))
+	if err != nil {
+		return ""
+	}
+	if sha1 := strings.TrimSpace(string(b)); checksum.Valid(checksum.SHA1, sha1) {
+		return sha1
+	}
+	return ""
+}
+
+// @brief Put moves a verified file into the cache.
+// @param path file to store; it is moved, or copied when on another device
+// @param sum hashes of the file; SHA1 is required, SHA512 adds a pointer
+// @return path of the cached file, or error if a hash is malformed
+func (c *Cache) Put(path string, sum checksum.Sums) (string, error) {
+	if !checksum.Valid(checksum.SHA1, sum.SHA1) {
+		return "", fmt.Errorf("cannot cache %s: malformed sha1 %q", filepath.Base(path), sum.SHA1)
+	}
+	if err := sum.Validate(); err != nil {
+		return "", fmt.Errorf("cannot cache %s: %w", filepath.Base(path), err)
+	}
+	dst := c.blob(sum.SHA1)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dst); err == nil {
+		os.Remove(path) // another install got there first
+	} else if err := os.Rename(path, dst); err != nil {
+		if err := fsutil.CopyFile(path, dst); err != nil {
+			return "", err
+		}
+		os.Remove(path)
+	}
+
+	if sum.SHA512 != "" {
+		ptr := c.pointer(sum.SHA512)
+		if err := os.MkdirAll(filepath.Dir(ptr), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(ptr, []byte(sum.SHA1), 0o644); err != nil {
+			return "", err
+		}
+	}
+	return dst, nil
+}
+
+// @brief Partial returns the folder for downloads still in progress.
+func (c *Cache) Partial() string {
+	return filepath.Join(c.dir, partialDir)
+}
+
+// @brief Holds reports whether a path is a file inside the cache, which
+// callers must not delete.
+func (c *Cache) Holds(path string) bool {
+	if c == nil {
+		return false
+	}
+	rel, err := filepath.Rel(c.dir, path)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
+
+// @brief Register remembers a manifest so gc keeps the files it references.
+// @param manifestPath manifest file
+// @return error if the list could not be written
+func (c *Cache) Register(manifestPath string) error {
+	abs, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	known, err := c.Manifests()
+	if err != nil {
+		return err
+	}
+	for _, k := range known {
+		if k == abs {
+			return nil
+		}
+	}
+	return c.writeManifests(append(known, abs))
+}
+
+// @brief Manifests lists the manifests that have used the cache.
+// @return absolute manifest paths (some may no longer exist), or error
+func (c *Cache) Manifests() ([]string, error) {
+	b, err := os.ReadFile(filepath.Join(c.dir, manifestList))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestList, err)
+	}
+	return out, nil
+}
+
+func (c *Cache) writeManifests(paths []string) error {
+	sort.Strings(paths)
+	b, _ := json.MarshalIndent(paths, "", " ")
+	tmp, err := os.CreateTemp(c.dir, ".manifests-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, manifestList))
+}
+
+// Stats summarises the cache.
+type Stats struct {
+	Files     int
+	Bytes     int64
+	Manifests int
+}
+
+// @brief Stats counts the cached files and their size.
+// @return stats or error if the cache could not be read
+func (c *Cache) Stats() (Stats, error) {
+	var s Stats
+	err := c.walk(func(_, _ string, info fs.FileInfo) error {
+		s.Files++
+		s.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return s, err
+	}
+	known, err := c.Manifests()
+	s.Manifests = len(known)
+	return s, err
+}
+
+// @brief Verify hashes every cached file and removes the ones that do not
+// match their name, along with dangling sha512 pointers.
+// @return hashes of the removed files, or error
+func (c *Cache) Verify() ([]string, error) {
+	var bad []string
+	err := c.walk(func(path, sha1 string, _ fs.FileInfo) error {
+		got, err := checksum.File(path)
+		if err != nil {
+			return err
+		}
+		if got.SHA1 != sha1 {
+			bad = append(bad, sha1)
+			return os.Remove(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return bad, err
+	}
+	return bad, c.prunePointers()
+}
+
+// @brief GC removes cached files that no known manifest references and
+// forgets manifests that no longer exist.
+// @param keep hashes still in use, SHA1 or SHA512
+// @param dryRun only report what would be removed
+// @return number of files and bytes removed, or error
+func (c *Cache) GC(keep map[string]bool, dryRun bool) (int, int64, error) {
+	for sum := range keep {
+		if !checksum.Valid(checksum.SHA1, sum) && !checksum.Valid(checksum.SHA512, sum) {
+			delete(keep, sum) // could never name a cached file
+			continue
+		}
+		if sha1 := c.resolve(sum); sha1 != "" {
+			keep[sha1] = true
+		}
+	}
+	var n int
+	var freed int64
+	err := c.walk(func(path, sha1 string, info fs.FileInfo) error {
+		if keep[sha1] {
+			return nil
+		}
+		n++
+		freed += info.Size()
+		if dryRun {
+			return nil
+		}
+		return os.Remove(path)
+	})
+	if err != nil || dryRun {
+		return n, freed, err
+	}
+	if err := c.prunePointers(); err != nil {
+		return n, freed, err
+	}
+	if err := c.prunePartials(); err != nil {
+		return n, freed, err
+	}
+	return n, freed, c.forgetMissing()
+}
+
+// forgetMissing drops deleted manifests from manifests.json.
+func (c *Cache) forgetMissing() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	known, err := c.Manifests()
+	if err != nil {
+		return err
+	}
+	var live []string
+	for _, k := range known {
+		if _, err := os.Stat(k); err == nil {
+			live = append(live, k)
+		}
+	}
+	if len(live) == len(known) {
+		return nil
+	}
+	return c.writeManifests(live)
+}
+
+// prunePartials removes interrupted downloads nobody resumed for a week.
+func (c *Cache) prunePartials() error {
+	files, err := os.ReadDir(filepath.Join(c.dir, partialDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, f := range files {
+		info, err := f.Info()
+		if err == nil && time.Since(info.ModTime()) > 7*24*time.Hour {
+			os.Remove(filepath.Join(c.dir, partialDir, f.Name()))
+		}
+	}
+	return nil
+}
+
+// prunePointers removes sha512 pointers whose file is gone.
+func (c *Cache) prunePointers() error {
+	root := filepath.Join(c.dir, sha512Dir)
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && p == root {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		sha1 := strings.TrimSpace(string(b))
+		if checksum.Valid(checksum.SHA1, sha1) {
+			if _, err := os.Stat(c.blob(sha1)); err == nil {
+				return nil
+			}
+		}
+		return os.Remove(p)
+	})
+}
+
+// walk calls fn for every cached file with its SHA1.
+func (c *Cache) walk(fn func(path, sha1 string, info fs.FileInfo) error) error {
+	root := filepath.Join(c.dir, sha1Dir)
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && p == root {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(p, d.Name(), info)
+	})
+}
+
+// @brief Place puts a copy of src at dst: a reflink where the filesystem
+// supports it, else a hardlink, else a plain copy. dst is replaced atomically.
+// @param src file to place, usually a cached one
+// @param dst destination path
+// @return error if every method failed
+func Place(src, dst string) error {
+	tmp := filepath.Join(filepath.Dir(dst), fmt.Sprintf(".mr-%d-%s", os.Getpid(), filepath.Base(dst)))
+	os.Remove(tmp)
+	if err := reflink(src, tmp); err == nil {
+		return rename(tmp, dst)
+	}
+	os.Remove(tmp)
+	if err := os.Link(src, tmp); err == nil {
+		return rename(tmp, dst)
+	}
+	if err := fsutil.CopyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return rename(tmp, dst)
+}
+
+func rename(tmp, dst string) error {
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+)
+
+func TestPutLookup(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "a.jar")
+	if err := os.WriteFile(src, []byte("jar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := checksum.File(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(src, sum); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]checksum.Sums{
+		"sha1":        {SHA1: sum.SHA1},
+		"sha512 only": {SHA512: sum.SHA512},
+	} {
+		if _, got, ok := c.Lookup(want); !ok || got != sum {
+			t.Errorf("Lookup by %s = %v, %v; want a hit", name, got, ok)
+		}
+	}
+}
+
+func TestMalformedHashes(t *testing.T) {
+	root := t.TempDir()
+	victim := filepath.Join(root, "victim.txt")
+	if err := os.WriteFile(victim, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Open(filepath.Join(root, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []checksum.Sums{
+		{SHA1: "../victim.txt"},
+		{SHA1: "../../victim.txt"},
+		{SHA512: "../victim.txt"},
+	} {
+		if _, _, ok := c.Lookup(want); ok {
+			t.Errorf("Lookup(%v) hit", want)
+		}
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Fatalf("Lookup removed a file outside the cache: %v", err)
+	}
+
+	src := filepath.Join(root, "a.jar")
+	if err := os.WriteFile(src, []byte("jar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(src, checksum.Sums{SHA1: "../x"}); err == nil {
+		t.Error("Put accepted a malformed sha1")
+	}
+	if n, _, err := c.GC(map[string]bool{"../victim.txt": true}, false); err != nil || n != 0 {
+		t.Errorf("GC = %d, %v", n, err)
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Fatalf("GC removed a file outside the cache: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package cache
+
+import "errors"
+
+// reflink is only implemented on Linux; Place falls back to a hardlink.
+func reflink(src, dst string) error {
+	return errors.New("reflink not supported")
+}
@@ -0,0 +1,204 @@
+package checksum
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// hash algorithms, named the way Modrinth and packwiz name them
+const (
+	SHA1   = "sha1"
+	SHA256 = "sha256"
+	SHA512 = "sha512"
+)
+
+// Sums are the hashes of one file; an empty field is unknown.
+type Sums struct {
+	SHA1   string
+	SHA512 string
+}
+
+// @brief Empty reports whether no hash is known.
+func (s Sums) Empty() bool {
+	return s.SHA1 == "" && s.SHA512 == ""
+}
+
+// hex digest lengths, by algorithm
+var sizes = map[string]int{SHA1: 2 * sha1.Size, SHA256: 2 * sha256.Size, SHA512: 2 * sha512.Size}
+
+// @brief Valid reports whether a string is a digest of an algorithm:
+// lowercase hex of exactly the algorithm's length.
+// @param algo "sha1", "sha256" or "sha512"
+// @param sum digest to check
+func Valid(algo, sum string) bool {
+	if len(sum) != sizes[algo] || len(sum) == 0 {
+		return false
+	}
+	for _, c := range sum {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// @brief Validate checks that every known hash is a well-formed digest.
+// @return error naming the first malformed hash
+func (s Sums) Validate() error {
+	if s.SHA1 != "" && !Valid(SHA1, s.SHA1) {
+		return fmt.Errorf("malformed sha1 %q", s.SHA1)
+	}
+	if s.SHA512 != "" && !Valid(SHA512, s.SHA512) {
+		return fmt.Errorf("malformed sha512 %q", s.SHA512)
+	}
+	return nil
+}
+
+// @brief Short returns a few hex digits for messages, SHA512 preferred.
+func (s Sums) Short() string {
+	sum := s.SHA512
+	if sum == "" {
+		sum = s.SHA1
+	}
+	if len(sum) > 8 {
+		return sum[:8]
+	}
+	return sum
+}
+
+// @brief Check compares actual hashes with the ones expected.
+// Every hash known on both sides must match.
+// @param got hashes of the file
+// @return nil on a match, an error naming the first differing algorithm,
+// or an error if there was nothing to compare
+func (s Sums) Check(got Sums) error {
+	compared := false
+	if s.SHA512 != "" && got.SHA512 != "" {
+		if !strings.EqualFold(s.SHA512, got.SHA512) {
+			return fmt.Errorf("sha512 mismatch (want %s, got %s)", s.SHA512, got.SHA512)
+		}
+		compared = true
+	}
+	if s.SHA1 != "" && got.SHA1 != "" {
+		if !strings.EqualFold(s.SHA1, got.SHA1) {
+			return fmt.Errorf("sha1 mismatch (want %s, got %s)", s.SHA1, got.SHA1)
+		}
+		compared = true
+	}
+	if !compared {
+		return fmt.Errorf("no hash to compare")
+	}
+	return nil
+}
+
+// Writer hashes everything written to it with every algorithm in Sums.
+type Writer struct {
+	h1, h512 hash.Hash
+}
+
+// @brief NewWriter returns a Writer for io.Copy / io.MultiWriter.
+func NewWriter() *Writer {
+	return &Writer{h1: sha1.New(), h512: sha512.New()}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.h1.Write(p)
+	w.h512.Write(p)
+	return len(p), nil
+}
+
+// @brief Sums returns the hashes of what was written so far.
+func (w *Writer) Sums() Sums {
+	return Sums{SHA1: hex.EncodeToString(w.h1.Sum(nil)), SHA512: hex.EncodeToString(w.h512.Sum(nil))}
+}
+
+// @brief Reader hashes a stream to its end.
+// @param r data to hash
+// @return SHA1 and SHA512, or error
+func Reader(r io.Reader) (Sums, error) {
+	w := NewWriter()
+	if _, err := io.Copy(w, r); err != nil {
+		return Sums{}, err
+	}
+	return w.Sums(), nil
+}
+
+// @brief File hashes a file with SHA1 and SHA512 in one pass.
+// @param path file to hash
+// @return hashes or error
+func File(path string) (Sums, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Sums{}, err
+	}
+	defer f.Close()
+	return Reader(f)
+}
+
+// @brief New creates a hash by name.
+// @param algo "sha1", "sha256" or "sha512" (any case)
+// @return hash or error if the algorithm is unsupported
+func New(algo string) (hash.Hash, error) {
+	switch strings.ToLower(algo) {
+	case SHA1:
+		return sha1.New(), nil
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash format %q", algo)
+	}
+}
+
+// @brief FileWith hashes a file with one algorithm.
+// @param path file to hash
+// @param algo algorithm name, see New
+// @return hex digest or error
+func FileWith(path, algo string) (string, error) {
+	h, err := New(algo)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// errNoChecksum is returned when asked to find a file without a hash to look for.
+var errNoChecksum = fmt.Errorf("no checksum recorded")
+
+// @brief Matches checks whether a file exists and has the expected hashes.
+// @param path file to check
+// @param want expected hashes
+// @return true if it exists and matches, false if it is missing, differs
+// or want is empty, or error if it could not be read
+func Matches(path string, want Sums) (bool, error) {
+	return (*Index)(nil).Matches(path, want)
+}
+
+// @brief Find scans a folder for a file with the expected hashes.
+// @param dir folder to scan
+// @param want expected hashes
+// @param accept filter on file names, nil accepts everything
+// @return matching file name or error
+func Find(dir string, want Sums, accept func(name string) bool) (string, error) {
+	return (*Index)(nil).Find(dir, want, accept)
+}
+
+func notFound(dir string, want Sums) error {
+	return fmt.Errorf("no file with checksum %s found in %s", want.Short(), dir)
+}
@@ -0,0 +1,28 @@
+package checksum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	sha1 := strings.Repeat("a1", 20)
+	tests := []struct {
+		algo, sum string
+		want      bool
+	}{
+		{SHA1, sha1, true},
+		{SHA512, strings.Repeat("0f", 64), true},
+		{SHA1, "", false},
+		{SHA1, sha1[:39], false},
+		{SHA1, strings.ToUpper(sha1), false},
+		{SHA1, "../victim.txt" + sha1[13:], false},
+		{SHA512, sha1, false},
+		{"md5", sha1, false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.algo, tt.sum); got != tt.want {
+			t.Errorf("Valid(%s, %q) = %v, want %v", tt.algo, tt.sum, got, tt.want)
+		}
+	}
+}
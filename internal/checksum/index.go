@@ -0,0 +1,262 @@
+package checksum
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// IndexFile is the hash index's file name, kept in a game dir's state folder.
+const IndexFile = "hashes.json"
+
+// hashWorkers bounds how many files Index.Hash reads at once.
+const hashWorkers = 4
+
+// Index remembers the hashes of files by path, size and modification time,
+// so unchanged files are not read again. A nil *Index hashes every time.
+type Index struct {
+	root  string // keys are relative to it
+	path  string
+	mu    sync.Mutex
+	files map[string]indexed // nil until loaded
+	dirty bool
+}
+
+type indexed struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"` // unix nanoseconds
+	SHA1    string `json:"sha1"`
+	SHA512  string `json:"sha512"`
+}
+
+type indexFile struct {
+	Files map[string]indexed `json:"files"`
+}
+
+// @brief OpenIndex returns the index stored at path. It is read on first use;
+// a missing or corrupt file starts an empty one.
+// @param root folder the indexed files live under, usually the game dir
+// @param path index file
+func OpenIndex(root, path string) *Index {
+	return &Index{root: root, path: path}
+}
+
+// @brief File returns a file's hashes, reading it only if its size or
+// modification time changed since it was last hashed.
+// @param path file to hash
+// @return hashes or error
+func (x *Index) File(path string) (Sums, error) {
+	if x == nil {
+		return File(path)
+	}
+	key := x.key(path)
+	st, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			x.forget(key)
+		}
+		return Sums{}, err
+	}
+
+	x.mu.Lock()
+	x.load()
+	e, ok := x.files[key]
+	x.mu.Unlock()
+	if ok && e.Size == st.Size() && e.ModTime == st.ModTime().UnixNano() {
+		return Sums{SHA1: e.SHA1, SHA512: e.SHA512}, nil
+	}
+
+	sum, err := File(path)
+	if err != nil {
+		return Sums{}, err
+	}
+	x.store(key, st, sum)
+	return sum, nil
+}
+
+// @brief Record adds a file whose hashes are already known, such as a
+// download that was just verified and placed.
+// @param path file on disk
+// @param sum its hashes
+func (x *Index) Record(path string, sum Sums) {
+	if x == nil {
+		return
+	}
+	if st, err := os.Stat(path); err == nil {
+		x.store(x.key(path), st, sum)
+	}
+}
+
+// @brief Hash brings the index up to date for many files at once, reading
+// changed ones in parallel.
+// @param paths files to hash; missing ones are skipped
+// @return the first error other than a missing file
+func (x *Index) Hash(paths []string) error {
+	if x == nil {
+		return nil
+	}
+	var g errgroup.Group
+	g.SetLimit(hashWorkers)
+	for _, p := range paths {
+		g.Go(func() error {
+			if _, err := x.File(p); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// @brief Matches is the package-level Matches through the index.
+func (x *Index) Matches(path string, want Sums) (bool, error) {
+	if want.Empty() {
+		return false, nil
+	}
+	got, err := x.File(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return want.Check(got) == nil, nil
+}
+
+// @brief Find is the package-level Find through the index. The folder's
+// files are hashed in parallel first, then compared in name order.
+func (x *Index) Find(dir string, want Sums, accept func(name string) bool) (string, error) {
+	if want.Empty() {
+		return "", errNoChecksum
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	x.prune(dir, entries)
+	var names, paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || (accept != nil && !accept(entry.Name())) {
+			continue
+		}
+		names = append(names, entry.Name())
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	if err := x.Hash(paths); err != nil {
+		return "", err
+	}
+	for i, p := range paths {
+		ok, err := x.Matches(p, want)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return names[i], nil
+		}
+	}
+	return "", notFound(dir, want)
+}
+
+// @brief Save writes the index if anything changed since it was read.
+// @return error if it could not be written
+func (x *Index) Save() error {
+	if x == nil {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(x.path), 0o755); err != nil {
+		return err
+	}
+	b, _ := json.MarshalIndent(indexFile{Files: x.files}, "", " ")
+	tmp, err := os.CreateTemp(filepath.Dir(x.path), ".hashes-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), x.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	x.dirty = false
+	return nil
+}
+
+// key names a file relative to the root, so the index survives moving the game dir.
+func (x *Index) key(path string) string {
+	if rel, err := filepath.Rel(x.root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// load reads the index file once; callers hold mu.
+func (x *Index) load() {
+	if x.files != nil {
+		return
+	}
+	var f indexFile
+	if b, err := os.ReadFile(x.path); err == nil && json.Unmarshal(b, &f) == nil && f.Files != nil {
+		x.files = f.Files
+		return
+	}
+	x.files = map[string]indexed{} // missing or corrupt: start over
+}
+
+func (x *Index) store(key string, st os.FileInfo, sum Sums) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+	x.files[key] = indexed{Size: st.Size(), ModTime: st.ModTime().UnixNano(), SHA1: sum.SHA1, SHA512: sum.SHA512}
+	x.dirty = true
+}
+
+// prune drops the entries of files that are no longer in a folder.
+func (x *Index) prune(dir string, entries []os.DirEntry) {
+	if x == nil {
+		return
+	}
+	present := map[string]bool{}
+	for _, e := range entries {
+		present[e.Name()] = true
+	}
+	prefix := x.key(dir) + "/"
+	if prefix == "./" {
+		prefix = "" // the root's own files have bare keys
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+	for key := range x.files {
+		name, ok := strings.CutPrefix(key, prefix)
+		if ok && !strings.Contains(name, "/") && !present[name] {
+			delete(x.files, key)
+			x.dirty = true
+		}
+	}
+}
+
+func (x *Index) forget(key string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.load()
+	if _, ok := x.files[key]; ok {
+		delete(x.files, key)
+		x.dirty = true
+	}
+}
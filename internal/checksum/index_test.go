@@ -0,0 +1,132 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndexInvalidation(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "mods", "a.jar")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fake := Sums{SHA1: "cached", SHA512: "cached"}
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T) // applied after recording fake sums for "aaaa"
+		cached bool               // File still returns the recorded sums
+	}{
+		{name: "unchanged", change: func(*testing.T) {}, cached: true},
+		{name: "same size, new mtime", change: func(t *testing.T) {
+			write(t, file, "bbbb", mtime.Add(time.Minute))
+		}},
+		{name: "new size, same mtime", change: func(t *testing.T) {
+			write(t, file, "aaaaa", mtime)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := OpenIndex(root, filepath.Join(root, IndexFile))
+			write(t, file, "aaaa", mtime)
+			x.Record(file, fake)
+			tt.change(t)
+
+			got, err := x.File(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := File(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.cached {
+				want = fake
+			}
+			if got != want {
+				t.Errorf("File = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestIndexPersistence(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "a.jar")
+	path := filepath.Join(root, ".modcli", IndexFile)
+	fake := Sums{SHA1: "cached"}
+	write(t, file, "aaaa", time.Now().Add(-time.Hour))
+
+	x := OpenIndex(root, path)
+	x.Record(file, fake)
+	if err := x.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := OpenIndex(root, path).File(file); got != fake {
+		t.Errorf("reopened index: File = %v, want the saved %v", got, fake)
+	}
+
+	// a deleted file is forgotten
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	x = OpenIndex(root, path)
+	if _, err := x.File(file); !os.IsNotExist(err) {
+		t.Fatalf("File of a deleted file: %v", err)
+	}
+	if err := x.Save(); err != nil {
+		t.Fatal(err)
+	}
+	write(t, file, "aaaa", time.Now().Add(-time.Hour))
+	want, _ := File(file)
+	if got, _ := OpenIndex(root, path).File(file); got != want {
+		t.Errorf("after delete and rewrite: File = %v, want %v", got, want)
+	}
+
+	// a corrupt index starts over
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := OpenIndex(root, path).File(file); err != nil || got != want {
+		t.Errorf("corrupt index: File = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestIndexFindPrunes(t *testing.T) {
+	root := t.TempDir()
+	x := OpenIndex(root, filepath.Join(root, IndexFile))
+	a, b := filepath.Join(root, "a.jar"), filepath.Join(root, "b.jar")
+	write(t, a, "aaaa", time.Now())
+	write(t, b, "bbbb", time.Now())
+	want, _ := File(b)
+
+	if name, err := x.Find(root, want, nil); err != nil || name != "b.jar" {
+		t.Fatalf("Find = %q, %v; want b.jar", name, err)
+	}
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := x.Find(root, want, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := x.files[x.key(a)]; ok {
+		t.Error("Find kept the entry of a deleted file")
+	}
+	if _, err := x.Find(root, Sums{SHA1: "none"}, nil); err == nil {
+		t.Error("Find matched a hash no file has")
+	}
+}
+
+func write(t *testing.T, p, body string, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(p, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
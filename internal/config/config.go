@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Config holds per-user settings that do not belong in a shared manifest,
+// such as API keys. It lives in <user config dir>/modrinth-cli/config.json.
+type Config struct {
+	CurseForge CurseForge `json:"curseforge"`
+	Cache      Cache      `json:"cache"`
+}
+
+type CurseForge struct {
+	APIKey  string `json:"api_key"`  // from console.curseforge.com
+	BaseURL string `json:"base_url"` // default https://api.curseforge.com/
+}
+
+// Cache configures the download cache shared by every game dir.
+type Cache struct {
+	Dir      string `json:"dir"`      // default <user cache dir>/modrinth-cli/artifacts
+	Disabled bool   `json:"disabled"` // always download
+}
+
+// @brief Path returns where the config file is read from.
+// @return absolute path or error if there is no user config dir
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "modrinth-cli", "config.json"), nil
+}
+
+// @brief Load reads the config file, applying defaults and environment overrides.
+// A missing file is not an error. $CURSEFORGE_API_KEY and $CURSEFORGE_API_URL
+// take precedence over the file.
+// @return config or error if the file exists but cannot be parsed
+func Load() (*Config, error) {
+	var c Config
+	if path, err := Path(); err == nil {
+		b, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(b, &c); err != nil {
+				return nil, err
+			}
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+
+	if v := os.Getenv("CURSEFORGE_API_KEY"); v != "" {
+		c.CurseForge.APIKey = v
+	}
+	if v := os.Getenv("CURSEFORGE_API_URL"); v != "" {
+		c.CurseForge.BaseURL = v
+	}
+	if v := os.Getenv("MODCLI_CACHE_DIR"); v != "" {
+		c.Cache.Dir = v
+	}
+	if c.CurseForge.BaseURL == "" {
+		c.CurseForge.BaseURL = "https://api.curseforge.com/"
+	}
+	return &c, nil
+}
@@ -0,0 +1,195 @@
+package curseforge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/silask7188/ModrinthCLI/internal/config"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// GameID is Minecraft's id on CurseForge.
+const GameID = 432
+
+const (
+	filesPageSize = 50
+	maxFilesIndex = 10000 // the API rejects index + pageSize beyond this
+)
+
+type Client struct {
+	base   *url.URL
+	apiKey string
+	http   *http.Client
+}
+
+// @brief New creates a CurseForge client.
+// @param base API base url ("https://api.curseforge.com/" or a local stand-in)
+// @param apiKey CurseForge Core API key
+// @return ready-to-use Client or error
+func New(base, apiKey string) (*Client, error) {
+	if apiKey == "" {
+		return nil, errors.New("curseforge: api key is required")
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	return &Client{
+		base:   u,
+		apiKey: apiKey,
+		http:   &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// @brief FromConfig creates a client from the user's config.
+// @param cfg loaded config
+// @return client or an error explaining how to set the key
+func FromConfig(cfg *config.Config) (*Client, error) {
+	if cfg.CurseForge.APIKey == "" {
+		path, _ := config.Path()
+		return nil, fmt.Errorf("curseforge api key not configured; set curseforge.api_key in %s or $CURSEFORGE_API_KEY", path)
+	}
+	return New(cfg.CurseForge.BaseURL, cfg.CurseForge.APIKey)
+}
+
+// @brief JSON request
+// @param ctx context for cancellation
+// @param method HTTP method (GET, POST, etc.)
+// @param path API path to call
+// @param params URL parameters to add
+// @param body request body (can be nil)
+// @param dest destination to decode the response into
+// @return error if the request failed or the response could not be decoded
+func (c *Client) doJSON(
+	ctx context.Context,
+	method string,
+	path string,
+	params url.Values,
+	body io.Reader,
+	dest any,
+) error {
+
+	u := c.base.ResolveReference(&url.URL{Path: path, RawQuery: params.Encode()})
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, path, source.ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: unexpected status %s", method, path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// @brief GET json, unwrapping CurseForge's {"data": ...} envelope
+// @param ctx context for cancellation
+// @param c Client to use
+// @param path API path to call
+// @param params URL parameters to add
+// @return pointer to T with the response data or error
+func getJSON[T any](
+	ctx context.Context,
+	c *Client,
+	path string,
+	params url.Values,
+) (*T, error) {
+	var v struct {
+		Data T `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, path, params, nil, &v); err != nil {
+		return nil, err
+	}
+	return &v.Data, nil
+}
+
+// @brief GET /v1/mods/search
+// @param ctx context for cancellation
+// @param params query parameters (gameId is added)
+// @return matching mods or error
+func (c *Client) Search(ctx context.Context, params url.Values) ([]Mod, error) {
+	params.Set("gameId", fmt.Sprint(GameID))
+	out, err := getJSON[[]Mod](ctx, c, "v1/mods/search", params)
+	if err != nil {
+		return nil, err
+	}
+	return *out, nil
+}
+
+// @brief GET /v1/mods/{id}
+// @param ctx context for cancellation
+// @param id numeric mod id
+// @return mod or error
+func (c *Client) Mod(ctx context.Context, id int) (*Mod, error) {
+	return getJSON[Mod](ctx, c, fmt.Sprintf("v1/mods/%d", id), nil)
+}
+
+// @brief GET /v1/mods/{id}/files, every page
+// @param ctx context for cancellation
+// @param id numeric mod id
+// @param gameVersion optional Minecraft version filter
+// @param loader optional mod loader type filter (0 = any)
+// @return files or error
+func (c *Client) Files(ctx context.Context, id int, gameVersion string, loader LoaderType) ([]File, error) {
+	params := url.Values{}
+	params.Set("pageSize", fmt.Sprint(filesPageSize))
+	if gameVersion != "" {
+		params.Set("gameVersion", gameVersion)
+	}
+	if loader != LoaderAny {
+		params.Set("modLoaderType", fmt.Sprint(int(loader)))
+	}
+
+	var files []File
+	for {
+		params.Set("index", fmt.Sprint(len(files)))
+		var page struct {
+			Data       []File `json:"data"`
+			Pagination struct {
+				ResultCount int `json:"resultCount"`
+				TotalCount  int `json:"totalCount"`
+			} `json:"pagination"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("v1/mods/%d/files", id), params, nil, &page); err != nil {
+			return nil, err
+		}
+		files = append(files, page.Data...)
+		if len(page.Data) == 0 || len(files) >= page.Pagination.TotalCount || len(files)+filesPageSize > maxFilesIndex {
+			return files, nil
+		}
+	}
+}
+
+// @brief GET /v1/mods/{id}/files/{fileId}
+// @param ctx context for cancellation
+// @param id numeric mod id
+// @param fileID numeric file id
+// @return file or error
+func (c *Client) File(ctx context.Context, id, fileID int) (*File, error) {
+	return getJSON[File](ctx, c, fmt.Sprintf("v1/mods/%d/files/%d", id, fileID), nil)
+}
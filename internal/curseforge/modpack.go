@@ -0,0 +1,64 @@
+package curseforge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PackManifestFile is the metadata file at the root of a CurseForge modpack export.
+const PackManifestFile = "manifest.json"
+
+// PackManifest is manifest.json of a CurseForge modpack export.
+type PackManifest struct {
+	Minecraft struct {
+		Version    string `json:"version"`
+		ModLoaders []struct {
+			ID      string `json:"id"` // "forge-47.2.0", "fabric-0.15.7"
+			Primary bool   `json:"primary"`
+		} `json:"modLoaders"`
+	} `json:"minecraft"`
+	ManifestType string     `json:"manifestType"` // "minecraftModpack"
+	Name         string     `json:"name"`
+	Version      string     `json:"version"`
+	Author       string     `json:"author"`
+	Files        []PackFile `json:"files"`
+	Overrides    string     `json:"overrides"` // folder in the zip, usually "overrides"
+}
+
+// PackFile is one CurseForge file a modpack depends on.
+type PackFile struct {
+	ProjectID int  `json:"projectID"`
+	FileID    int  `json:"fileID"`
+	Required  bool `json:"required"`
+}
+
+// @brief ReadPackManifest decodes manifest.json.
+// @param r manifest.json contents
+// @return manifest or error if it is not a modpack manifest
+func ReadPackManifest(r io.Reader) (*PackManifest, error) {
+	var m PackManifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s: %w", PackManifestFile, err)
+	}
+	if m.ManifestType != "minecraftModpack" {
+		return nil, fmt.Errorf("%s: manifest type %q is not a modpack", PackManifestFile, m.ManifestType)
+	}
+	if m.Overrides == "" {
+		m.Overrides = "overrides"
+	}
+	return &m, nil
+}
+
+// @brief Loader returns the primary mod loader.
+// @return loader name ("forge") and version ("47.2.0"), empty for vanilla packs
+func (m *PackManifest) Loader() (string, string) {
+	for _, l := range m.Minecraft.ModLoaders {
+		if l.Primary || len(m.Minecraft.ModLoaders) == 1 {
+			name, ver, _ := strings.Cut(l.ID, "-")
+			return name, ver
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,199 @@
+package curseforge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// Client is the CurseForge source.Provider.
+var _ source.Provider = (*Client)(nil)
+
+// @brief Name identifies CurseForge entries.
+func (c *Client) Name() string {
+	return source.CurseForge
+}
+
+// @brief SearchProjects runs a text search for Minecraft projects.
+// @param ctx context for cancellation
+// @param query search text
+// @param f filter; a single project type narrows by class
+// @param limit max results, 0 for the API default
+// @return matching projects or error
+func (c *Client) SearchProjects(ctx context.Context, query string, f source.Filter, limit int) ([]source.Project, error) {
+	params := url.Values{}
+	params.Set("searchFilter", query)
+	if f.GameVersion != "" {
+		params.Set("gameVersion", f.GameVersion)
+	}
+	if lt := ParseLoader(f.Loader); lt != LoaderAny {
+		params.Set("modLoaderType", fmt.Sprint(int(lt)))
+	}
+	if len(f.ProjectTypes) == 1 {
+		for id, name := range classes {
+			if name == f.ProjectTypes[0] {
+				params.Set("classId", fmt.Sprint(id))
+			}
+		}
+	}
+	if limit > 0 {
+		params.Set("pageSize", fmt.Sprint(limit))
+	}
+
+	mods, err := c.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]source.Project, 0, len(mods))
+	for i := range mods {
+		out = append(out, mods[i].toSource())
+	}
+	return out, nil
+}
+
+// @brief LookupProject fetches a project by numeric id or slug.
+// @param ctx context for cancellation
+// @param id numeric id or slug
+// @return project or error
+func (c *Client) LookupProject(ctx context.Context, id string) (*source.Project, error) {
+	mod, err := c.mod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p := mod.toSource()
+	return &p, nil
+}
+
+// @brief ListVersions lists a project's files as versions, newest first.
+// @param ctx context for cancellation
+// @param projectID numeric id or slug
+// @param f filter; loaders CurseForge does not know are not filtered on
+// @return versions or error
+func (c *Client) ListVersions(ctx context.Context, projectID string, f source.Filter) ([]source.Version, error) {
+	id, err := c.modID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := c.Files(ctx, id, f.GameVersion, ParseLoader(f.Loader))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].FileDate > files[j].FileDate
+	})
+	out := make([]source.Version, 0, len(files))
+	for _, file := range files {
+		out = append(out, source.Version{
+			ID:           strconv.Itoa(file.ID),
+			Number:       file.DisplayName,
+			Published:    file.FileDate,
+			GameVersions: file.GameVersions,
+		})
+	}
+	return out, nil
+}
+
+// @brief ResolveFiles returns the single file behind a CurseForge "version".
+// Files whose author turned off third-party downloads are not resolved; the
+// error names the file so it can be downloaded by hand.
+// @param ctx context for cancellation
+// @param projectID numeric id or slug
+// @param versionID numeric file id
+// @return one release or error
+func (c *Client) ResolveFiles(ctx context.Context, projectID, versionID string) ([]source.Release, error) {
+	mod, err := c.mod(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	fileID, err := strconv.Atoi(versionID)
+	if err != nil {
+		return nil, fmt.Errorf("curseforge file id %q is not a number", versionID)
+	}
+	f, err := c.File(ctx, mod.ID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if !f.Distributable(mod) {
+		return nil, fmt.Errorf("%s: the author of %s does not allow third-party downloads; download it from %s and add it with 'mod add file:<path>'",
+			f.FileName, mod.Name, mod.Links.WebsiteURL)
+	}
+	return []source.Release{{
+		Version:       versionID,
+		VersionNumber: f.DisplayName,
+		Filename:      f.FileName,
+		URL:           f.DownloadURL,
+		SHA1:          f.SHA1(),
+	}}, nil
+}
+
+// @brief VerifyFile checks a file against the SHA1 CurseForge publishes.
+func (c *Client) VerifyFile(path string, rel *source.Release) error {
+	return source.VerifyHashes(path, rel)
+}
+
+// @brief mod fetches a mod by numeric id, or searches for it by slug.
+// A slug is only unique within a class, so the search keeps exact matches
+// and prefers them in slugClasses order.
+func (c *Client) mod(ctx context.Context, idOrSlug string) (*Mod, error) {
+	if id, err := strconv.Atoi(idOrSlug); err == nil {
+		return c.Mod(ctx, id)
+	}
+	mods, err := c.Search(ctx, url.Values{"slug": {idOrSlug}})
+	if err != nil {
+		return nil, err
+	}
+	for _, class := range slugClasses {
+		for i := range mods {
+			if mods[i].ClassID == class && strings.EqualFold(mods[i].Slug, idOrSlug) {
+				return &mods[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("curseforge project %q: %w", idOrSlug, source.ErrNotFound)
+}
+
+// @brief modID resolves a slug to its numeric id, without a lookup for numeric ids.
+func (c *Client) modID(ctx context.Context, idOrSlug string) (int, error) {
+	if id, err := strconv.Atoi(idOrSlug); err == nil {
+		return id, nil
+	}
+	mod, err := c.mod(ctx, idOrSlug)
+	if err != nil {
+		return 0, err
+	}
+	return mod.ID, nil
+}
+
+// @brief ProjectType names the mod's class the way Modrinth names project types.
+// @return "mod", "resourcepack", ... or "" for classes the manifest does not hold
+func (m *Mod) ProjectType() string {
+	return classes[m.ClassID]
+}
+
+// @brief Distributable reports whether third-party tools may download a file:
+// the API only hands out its URL when the author allows it.
+// @param m the mod the file belongs to
+func (f *File) Distributable(m *Mod) bool {
+	return f.DownloadURL != "" && (m.AllowModDistribution == nil || *m.AllowModDistribution)
+}
+
+// @brief toSource converts a mod to the provider-neutral form.
+func (m *Mod) toSource() source.Project {
+	p := source.Project{
+		ID:          strconv.Itoa(m.ID),
+		Slug:        m.Slug,
+		Title:       m.Name,
+		Description: m.Summary,
+		ProjectType: m.ProjectType(),
+		URL:         m.Links.WebsiteURL,
+	}
+	if len(m.Authors) > 0 {
+		p.Author = m.Authors[0].Name
+	}
+	return p
+}
@@ -0,0 +1,109 @@
+package curseforge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testAPI stands in for the CurseForge API: mod 3 is the "jei" mod with 120
+// files, mod 4 does not allow third-party downloads.
+func testAPI(t *testing.T) *Client {
+	t.Helper()
+	reply := func(w http.ResponseWriter, v any) {
+		json.NewEncoder(w).Encode(v)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/v1/mods/search":
+			reply(w, map[string]any{"data": []Mod{
+				{ID: 1, Slug: "jei", ClassID: ClassModpacks},
+				{ID: 2, Slug: "jei-addon", ClassID: ClassMods},
+				{ID: 3, Slug: "jei", ClassID: ClassMods},
+			}})
+		case "/v1/mods/3":
+			reply(w, map[string]any{"data": Mod{ID: 3, Name: "JEI", Slug: "jei", ClassID: ClassMods}})
+		case "/v1/mods/4":
+			no := false
+			reply(w, map[string]any{"data": Mod{ID: 4, Name: "Closed", Slug: "closed", ClassID: ClassMods, AllowModDistribution: &no}})
+		case "/v1/mods/3/files":
+			const total = 120
+			index, _ := strconv.Atoi(q.Get("index"))
+			size, _ := strconv.Atoi(q.Get("pageSize"))
+			var page []File
+			for i := index; i < total && i < index+size; i++ {
+				page = append(page, File{ID: 1000 + i, ModID: 3})
+			}
+			reply(w, map[string]any{"data": page, "pagination": map[string]int{"index": index, "pageSize": size, "resultCount": len(page), "totalCount": total}})
+		case "/v1/mods/3/files/10":
+			reply(w, map[string]any{"data": File{ID: 10, ModID: 3, FileName: "jei-10.jar", DownloadURL: "https://edge.forgecdn.net/files/0/10/jei-10.jar"}})
+		case "/v1/mods/3/files/11":
+			reply(w, map[string]any{"data": File{ID: 11, ModID: 3, FileName: "jei-11.jar"}})
+		case "/v1/mods/4/files/12":
+			reply(w, map[string]any{"data": File{ID: 12, ModID: 4, FileName: "closed-12.jar", DownloadURL: "https://edge.forgecdn.net/files/0/12/closed-12.jar"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c, err := New(srv.URL, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestModBySlug(t *testing.T) {
+	c := testAPI(t)
+	m, err := c.mod(context.Background(), "JEI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ID != 3 {
+		t.Errorf("mod(jei) = %d, want the mod-class match 3", m.ID)
+	}
+	if _, err := c.mod(context.Background(), "jei-add"); err == nil {
+		t.Error("mod matched a slug that is only a prefix")
+	}
+}
+
+func TestFilesPages(t *testing.T) {
+	files, err := testAPI(t).Files(context.Background(), 3, "", LoaderAny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 120 || files[0].ID != 1000 || files[119].ID != 1119 {
+		t.Errorf("got %d files, want all 120 in order", len(files))
+	}
+}
+
+func TestResolveFiles(t *testing.T) {
+	c := testAPI(t)
+	tests := []struct {
+		mod, file string
+		wantErr   string // part of the error, "" for success
+	}{
+		{mod: "3", file: "10"},
+		{mod: "3", file: "11", wantErr: "jei-11.jar"},    // no download url
+		{mod: "4", file: "12", wantErr: "closed-12.jar"}, // author opted out
+	}
+	for _, tt := range tests {
+		rels, err := c.ResolveFiles(context.Background(), tt.mod, tt.file)
+		name := fmt.Sprintf("%s/%s", tt.mod, tt.file)
+		if tt.wantErr == "" {
+			if err != nil || len(rels) != 1 || rels[0].URL == "" {
+				t.Errorf("%s: got %+v, %v", name, rels, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want one naming %s", name, err, tt.wantErr)
+		}
+	}
+}
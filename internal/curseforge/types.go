@@ -0,0 +1,102 @@
+package curseforge
+
+import "strings"
+
+// class ids of the content types we support
+const (
+	ClassMods          = 6
+	ClassResourcePacks = 12
+	ClassShaders       = 6552
+	ClassDatapacks     = 6945
+	ClassPlugins       = 5 // Bukkit plugins
+	ClassModpacks      = 4471
+)
+
+// slugClasses are the classes a slug is looked up in, most likely first;
+// modpacks come last so 'mod add' can say why it refuses them.
+var slugClasses = []int{ClassMods, ClassPlugins, ClassResourcePacks, ClassShaders, ClassDatapacks, ClassModpacks}
+
+// classes maps class ids to content type names.
+var classes = map[int]string{
+	ClassMods:          "mod",
+	ClassResourcePacks: "resourcepack",
+	ClassShaders:       "shader",
+	ClassDatapacks:     "datapack",
+	ClassPlugins:       "plugin",
+	ClassModpacks:      "modpack",
+}
+
+// LoaderType is CurseForge's modLoaderType enum.
+type LoaderType int
+
+const (
+	LoaderAny      LoaderType = 0
+	LoaderForge    LoaderType = 1
+	LoaderFabric   LoaderType = 4
+	LoaderQuilt    LoaderType = 5
+	LoaderNeoForge LoaderType = 6
+)
+
+// @brief ParseLoader maps a manifest loader name to CurseForge's enum.
+// @param name "fabric", "neoforge", ...
+// @return loader type, LoaderAny if CurseForge has no such loader
+func ParseLoader(name string) LoaderType {
+	switch strings.ToLower(name) {
+	case "forge":
+		return LoaderForge
+	case "fabric":
+		return LoaderFabric
+	case "quilt":
+		return LoaderQuilt
+	case "neoforge":
+		return LoaderNeoForge
+	}
+	return LoaderAny
+}
+
+// Mod is a CurseForge project.
+type Mod struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Slug    string   `json:"slug"`
+	Summary string   `json:"summary"`
+	ClassID int      `json:"classId"`
+	Authors []Author `json:"authors"`
+	Links   struct {
+		WebsiteURL string `json:"websiteUrl"`
+	} `json:"links"`
+	AllowModDistribution *bool `json:"allowModDistribution"` // nil = unknown
+}
+
+type Author struct {
+	Name string `json:"name"`
+}
+
+// File is one uploaded file of a mod.
+type File struct {
+	ID           int      `json:"id"`
+	ModID        int      `json:"modId"`
+	DisplayName  string   `json:"displayName"`
+	FileName     string   `json:"fileName"`
+	FileDate     string   `json:"fileDate"`
+	FileLength   int64    `json:"fileLength"`
+	DownloadURL  string   `json:"downloadUrl"` // empty when the author disabled third-party downloads
+	GameVersions []string `json:"gameVersions"`
+	Hashes       []Hash   `json:"hashes"`
+}
+
+// Hash is one checksum of a file.
+type Hash struct {
+	Value string `json:"value"`
+	Algo  int    `json:"algo"` // 1 = sha1, 2 = md5
+}
+
+// @brief SHA1 returns the file's SHA1 hash, if CurseForge has one.
+func (f *File) SHA1() string {
+	for _, h := range f.Hashes {
+		if h.Algo == 1 {
+			return h.Value
+		}
+	}
+	return ""
+}
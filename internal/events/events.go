@@ -0,0 +1,114 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Kind says what happened to an entry.
+type Kind string
+
+const (
+	Resolve  Kind = "resolve"  // looking up the file to install
+	Progress Kind = "progress" // download progress, Bytes of Total
+	Verified Kind = "verified" // download matched its hashes
+	Placed   Kind = "placed"   // file put into Dest
+	Present  Kind = "present"  // the right file was already there
+	Skipped  Kind = "skipped"  // left out, Message says why
+	Failed   Kind = "failed"   // gave up, Message is the error
+	Override Kind = "override" // override file Dest copied into the game dir
+	Warning  Kind = "warning"  // something the user should look at
+)
+
+// Event is one step of an install or export.
+type Event struct {
+	Kind    Kind   `json:"event"`
+	Slug    string `json:"slug,omitempty"`
+	Version string `json:"version,omitempty"` // version number, when known
+	Dest    string `json:"dest,omitempty"`    // folder relative to the game dir, or an override path
+	Bytes   int64  `json:"bytes,omitempty"`   // Progress: bytes so far
+	Total   int64  `json:"total,omitempty"`   // Progress: file size, 0 if unknown
+	Message string `json:"message,omitempty"` // Skipped, Failed, Warning
+}
+
+// Sink receives installer events. Implementations must be safe for
+// concurrent use, installs run several entries at once.
+type Sink interface {
+	Event(Event)
+}
+
+// Discard drops every event.
+type Discard struct{}
+
+func (Discard) Event(Event) {}
+
+// @brief NewPlain logs finished steps as lines, the way the CLI always has.
+// Resolve, progress, verified and present events are left out.
+// @param w output, usually stdout
+func NewPlain(w io.Writer) Sink {
+	return &plain{w: w}
+}
+
+type plain struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (p *plain) Event(ev Event) {
+	line := Line(ev)
+	if line == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.w, line)
+}
+
+// @brief Line formats an event as a log line.
+// @return the line, or "" for events that are not logged
+func Line(ev Event) string {
+	switch ev.Kind {
+	case Placed:
+		switch {
+		case ev.Dest != "" && ev.Version != "":
+			return fmt.Sprintf("[+] %s -> %s (%s)", ev.Slug, ev.Dest, ev.Version)
+		case ev.Dest != "":
+			return fmt.Sprintf("[+] %s -> %s", ev.Slug, ev.Dest)
+		case ev.Version != "":
+			return fmt.Sprintf("[+] %s (%s)", ev.Slug, ev.Version)
+		}
+		return fmt.Sprintf("[+] %s", ev.Slug)
+	case Skipped:
+		return fmt.Sprintf("[-] %s skipped (%s)", ev.Slug, ev.Message)
+	case Failed:
+		return fmt.Sprintf("[!] %s failed: %s", ev.Slug, ev.Message)
+	case Override:
+		return fmt.Sprintf("[+] override %s", ev.Dest)
+	case Warning:
+		if ev.Slug != "" {
+			return fmt.Sprintf("[!] %s: %s", ev.Slug, ev.Message)
+		}
+		return fmt.Sprintf("[!] %s", ev.Message)
+	}
+	return ""
+}
+
+// @brief NewJSON writes every event as one JSON object per line, for
+// wrappers and GUIs.
+// @param w output, usually stdout
+func NewJSON(w io.Writer) Sink {
+	return &jsonSink{enc: json.NewEncoder(w)}
+}
+
+type jsonSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (j *jsonSink) Event(ev Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.enc.Encode(ev)
+}
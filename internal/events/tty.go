@@ -0,0 +1,128 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redrawEvery limits how often progress alone repaints the live lines.
+const redrawEvery = 100 * time.Millisecond
+
+// @brief IsTerminal reports whether f is an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
+
+// @brief NewTTY shows finished steps as log lines with one live line per
+// entry in flight below them, repainted as downloads progress.
+// @param w a terminal that understands ANSI escapes
+func NewTTY(w io.Writer) Sink {
+	return &tty{w: w, active: map[string]*status{}}
+}
+
+type status struct {
+	state        string
+	bytes, total int64
+}
+
+type tty struct {
+	mu     sync.Mutex
+	w      io.Writer
+	order  []string // active slugs, oldest first
+	active map[string]*status
+	drawn  int // live lines currently on screen
+	last   time.Time
+}
+
+func (t *tty) Event(ev Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch ev.Kind {
+	case Resolve:
+		t.set(ev.Slug, &status{state: "resolving"})
+	case Progress:
+		t.set(ev.Slug, &status{state: "downloading", bytes: ev.Bytes, total: ev.Total})
+		if time.Since(t.last) < redrawEvery {
+			return
+		}
+	case Verified, Placed, Present, Skipped, Failed:
+		t.done(ev.Slug)
+	}
+
+	t.clear()
+	if line := Line(ev); line != "" {
+		fmt.Fprintln(t.w, line)
+	}
+	t.draw()
+}
+
+func (t *tty) set(slug string, s *status) {
+	if _, ok := t.active[slug]; !ok {
+		t.order = append(t.order, slug)
+	}
+	t.active[slug] = s
+}
+
+func (t *tty) done(slug string) {
+	if _, ok := t.active[slug]; !ok {
+		return
+	}
+	delete(t.active, slug)
+	for i, s := range t.order {
+		if s == slug {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// clear erases the live lines so a log line can take their place.
+func (t *tty) clear() {
+	if t.drawn > 0 {
+		fmt.Fprintf(t.w, "\x1b[%dA\x1b[J", t.drawn)
+		t.drawn = 0
+	}
+}
+
+func (t *tty) draw() {
+	for _, slug := range t.order {
+		s := t.active[slug]
+		fmt.Fprintf(t.w, "  %-28s %s\n", slug, s.describe())
+	}
+	t.drawn = len(t.order)
+	t.last = time.Now()
+}
+
+func (s *status) describe() string {
+	if s.state != "downloading" {
+		return s.state + "..."
+	}
+	if s.total <= 0 {
+		return size(s.bytes)
+	}
+	const width = 24
+	n := int(s.bytes * width / s.total)
+	if n > width {
+		n = width
+	}
+	return fmt.Sprintf("[%s%s] %3d%% %s / %s", strings.Repeat("=", n), strings.Repeat(" ", width-n),
+		s.bytes*100/s.total, size(s.bytes), size(s.total))
+}
+
+func size(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,55 @@
+package fsutil
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// @brief WriteFrom writes everything r yields to dst, creating its folder.
+// A failed copy does not leave a partial dst behind.
+// @param dst file to create or replace
+// @param r content
+// @return error if the folder or file could not be written
+func WriteFrom(dst string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// @brief CopyFile copies src to dst, creating dst's folder.
+// @param src file to copy
+// @param dst file to create or replace
+// @return error if src could not be read or dst written
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return WriteFrom(dst, in)
+}
+
+// @brief ExtractFile writes a zip entry to dst, creating dst's folder.
+// @param f zip entry
+// @param dst file to create or replace
+// @return error if the entry could not be read or dst written
+func ExtractFile(f *zip.File, dst string) error {
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return WriteFrom(dst, in)
+}
@@ -0,0 +1,339 @@
+package installer
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/curseforge"
+	"github.com/silask7188/ModrinthCLI/internal/fsutil"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// CFReport is the outcome of a CurseForge modpack import.
+type CFReport struct {
+	Name      string
+	Version   string
+	Matched   []PackEntry // found on Modrinth by hash
+	Kept      []PackEntry // left on CurseForge
+	Overrides []string    // files extracted to overrides/
+	Unmatched []Unmatched // neither matched nor kept
+}
+
+// Unmatched is a file or project that could not be moved to Modrinth.
+type Unmatched struct {
+	Name        string   // file name or CurseForge project title
+	From        string   // "overrides", the local mods folder, or "curseforge"
+	Suggestions []string // Modrinth slugs that may be the same project
+}
+
+// candidate is a content file that may exist on Modrinth.
+type candidate struct {
+	rel      string // slash path relative to the game dir
+	from     string
+	sha1     string
+	optional bool // a CurseForge file the pack leaves off by default
+}
+
+// packFile is a CurseForge file listed in the pack manifest.
+type packFile struct {
+	mod      *curseforge.Mod
+	file     *curseforge.File
+	required bool
+	ct       manifest.ContentType
+	known    bool // ct is set
+}
+
+// @brief ImportCurseForge fills the manifest from a CurseForge modpack export.
+// Content files in the overrides, in a local mods folder and the pack's
+// CurseForge files are matched to Modrinth by hash; a pack that lists
+// CurseForge files needs an API key to look them up.
+// CurseForge files that match nothing stay CurseForge entries unless their
+// author blocks third-party downloads. Matched entries are pinned.
+// @param ctx context for cancellation
+// @param zipPath the exported .zip (manifest.json + overrides/)
+// @param modsDir folder with the pack's installed jars, empty for none
+// @return report of what was matched, kept and not found, or error (also
+// when the pack lists CurseForge files and no API key is configured)
+func (ins *Installer) ImportCurseForge(ctx context.Context, zipPath, modsDir string) (*CFReport, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	mf, err := zr.Open(curseforge.PackManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: no %s, not a CurseForge modpack", zipPath, curseforge.PackManifestFile)
+	}
+	pm, err := curseforge.ReadPackManifest(mf)
+	mf.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(pm.Files) > 0 && ins.cf == nil {
+		return nil, fmt.Errorf("the pack lists %d CurseForge files, which cannot be matched or suggested without an api key: %w",
+			len(pm.Files), ins.missing[source.CurseForge])
+	}
+
+	loader, loaderVer := pm.Loader()
+	ins.man.Minecraft = manifest.Minecraft{Version: pm.Minecraft.Version, Loader: loader, LoaderVersion: loaderVer}
+	if loader == "" {
+		ins.man.Minecraft.Loader = "vanilla"
+	}
+	rep := &CFReport{Name: pm.Name, Version: pm.Version}
+
+	// hash every content file we can see
+	prefix := pm.Overrides + "/"
+	var cands []candidate
+	for _, f := range zr.File {
+		rel, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok || f.FileInfo().IsDir() || !isContent(rel) {
+			continue
+		}
+		sum, err := zipSHA1(f)
+		if err != nil {
+			return nil, err
+		}
+		cands = append(cands, candidate{rel: rel, from: "overrides", sha1: sum})
+	}
+	if modsDir != "" {
+		files, err := os.ReadDir(modsDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			rel := path.Join("mods", f.Name())
+			if f.IsDir() || !isContent(rel) {
+				continue
+			}
+			sum, err := fileSHA1(filepath.Join(modsDir, f.Name()))
+			if err != nil {
+				return nil, err
+			}
+			cands = append(cands, candidate{rel: rel, from: modsDir, sha1: sum})
+		}
+	}
+
+	// the pack's CurseForge files: their hashes are looked up with the local ones
+	var cfFiles []packFile
+	for _, f := range pm.Files {
+		mod, err := ins.cf.Mod(ctx, f.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("curseforge project %d: %w", f.ProjectID, err)
+		}
+		file, err := ins.cf.File(ctx, f.ProjectID, f.FileID)
+		if err != nil {
+			return nil, fmt.Errorf("curseforge file %d/%d: %w", f.ProjectID, f.FileID, err)
+		}
+		pf := packFile{mod: mod, file: file, required: f.Required}
+		pf.ct, pf.known = manifest.TypeForProject(mod.ProjectType())
+		cfFiles = append(cfFiles, pf)
+		if pf.known && file.SHA1() != "" {
+			cands = append(cands, candidate{rel: path.Join(pf.ct.Dest, file.FileName), from: "curseforge", sha1: file.SHA1(), optional: !f.Required})
+		}
+	}
+
+	matched, err := ins.matchModrinth(ctx, cands)
+	if err != nil {
+		return nil, err
+	}
+	onCF := map[string]bool{} // sha1s of the pack's CurseForge files
+	for _, pf := range cfFiles {
+		onCF[pf.file.SHA1()] = true
+	}
+	seen := map[string]bool{} // sha1s now covered by an entry
+	slugs := map[string]bool{}
+	for _, c := range cands {
+		pe, ok := matched[c.sha1]
+		if !ok {
+			if c.from != "curseforge" && !onCF[c.sha1] {
+				rep.Unmatched = append(rep.Unmatched, ins.unmatched(ctx, path.Base(c.rel), c.from, searchTerms(path.Base(c.rel))))
+			}
+			continue
+		}
+		seen[c.sha1] = true
+		if slugs[pe.Entry.Slug] {
+			continue
+		}
+		slugs[pe.Entry.Slug] = true
+		pe.Entry.Enable = !c.optional
+		rep.Matched = append(rep.Matched, pe)
+	}
+
+	// CurseForge files Modrinth does not have stay CurseForge entries
+	for _, pf := range cfFiles {
+		sum := pf.file.SHA1()
+		if sum != "" && seen[sum] {
+			continue
+		}
+		switch {
+		case !pf.known:
+			rep.Unmatched = append(rep.Unmatched, ins.unmatched(ctx, pf.mod.Name, "curseforge", pf.mod.Name))
+		case !pf.file.Distributable(pf.mod):
+			rep.Unmatched = append(rep.Unmatched, ins.unmatched(ctx, pf.file.FileName, "curseforge, download by hand", pf.mod.Name))
+		default:
+			seen[sum] = true
+			rep.Kept = append(rep.Kept, PackEntry{Type: pf.ct, Entry: manifest.Entry{
+				Slug:          pf.mod.Slug,
+				Version:       strconv.Itoa(pf.file.ID),
+				VersionNumber: pf.file.DisplayName,
+				Dest:          pf.ct.Dest,
+				Checksum:      sum,
+				Filename:      pf.file.FileName,
+				Enable:        pf.required,
+				ProjectID:     strconv.Itoa(pf.mod.ID),
+				Source:        source.CurseForge,
+				Pinned:        true,
+			}})
+		}
+	}
+
+	for _, pe := range append(rep.Matched, rep.Kept...) {
+		if err := ins.man.AddEntry(pe.Type, pe.Entry); err != nil {
+			return nil, err
+		}
+	}
+
+	// everything else in the overrides ships as overrides
+	dest := filepath.Join(ins.man.Dir(), overrides.Common)
+	for _, f := range zr.File {
+		rel, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok || f.FileInfo().IsDir() {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return nil, fmt.Errorf("refusing to extract %q outside the pack", f.Name)
+		}
+		if isContent(rel) {
+			if sum, err := zipSHA1(f); err == nil && seen[sum] {
+				continue
+			}
+		}
+		if err := fsutil.ExtractFile(f, filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+			return nil, err
+		}
+		rep.Overrides = append(rep.Overrides, rel)
+	}
+	return rep, ins.man.Save()
+}
+
+// @brief matchModrinth looks candidates up on Modrinth by SHA1.
+// @param ctx context for cancellation
+// @param cands files to look up
+// @return pinned entries keyed by SHA1, for the files Modrinth knows
+func (ins *Installer) matchModrinth(ctx context.Context, cands []candidate) (map[string]PackEntry, error) {
+	hashes := make([]string, 0, len(cands))
+	for _, c := range cands {
+		hashes = append(hashes, c.sha1)
+	}
+	byHash, err := ins.mr.VersionsByHash(ctx, "sha1", hashes)
+	if err != nil {
+		return nil, fmt.Errorf("hash lookup failed: %w", err)
+	}
+	ids := make([]string, 0, len(byHash))
+	for _, v := range byHash {
+		ids = append(ids, v.ProjectID)
+	}
+	prjs, err := ins.mr.GetProjects(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("project lookup failed: %w", err)
+	}
+	byID := map[string]modrinth.Project{}
+	for _, p := range prjs {
+		byID[p.Id] = p
+	}
+
+	out := map[string]PackEntry{}
+	for _, c := range cands {
+		v, ok := byHash[c.sha1]
+		prj, found := byID[v.ProjectID]
+		if !ok || !found {
+			continue
+		}
+		ct, dest, _ := manifest.TypeForPath(c.rel)
+		out[c.sha1] = PackEntry{Type: ct, Entry: manifest.Entry{
+			Slug:          prj.Slug,
+			Version:       v.ID,
+			VersionNumber: v.VersionNumber,
+			Dest:          dest,
+			Checksum:      c.sha1,
+			Filename:      path.Base(c.rel),
+			Enable:        true,
+			ClientSide:    prj.ClientSide,
+			ServerSide:    prj.ServerSide,
+			ProjectID:     prj.Id,
+			Pinned:        true,
+		}}
+	}
+	return out, nil
+}
+
+// @brief unmatched builds a report line with Modrinth search suggestions.
+// A failed search only leaves the suggestions empty.
+// @param ctx context for cancellation
+// @param name what could not be matched
+// @param from where it was found
+// @param query text to search Modrinth for
+// @return report line
+func (ins *Installer) unmatched(ctx context.Context, name, from, query string) Unmatched {
+	u := Unmatched{Name: name, From: from}
+	if query == "" {
+		return u
+	}
+	hits, err := ins.mr.SearchProjects(ctx, query, source.Filter{GameVersion: ins.man.Minecraft.Version}, 3)
+	if err != nil {
+		return u
+	}
+	for _, h := range hits {
+		u.Suggestions = append(u.Suggestions, h.Slug)
+	}
+	return u
+}
+
+// @brief isContent reports whether a pack file sits in a content folder Modrinth may host.
+func isContent(rel string) bool {
+	ct, dest, _ := manifest.TypeForPath(rel)
+	return dest == ct.Dest && ct.Accepts(rel)
+}
+
+// @brief searchTerms turns a file name into a search query: "create-fabric-0.5.1.jar" -> "create".
+func searchTerms(name string) string {
+	name = strings.TrimSuffix(name, path.Ext(name))
+	var words []string
+	for _, w := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '+' || r == ' ' }) {
+		switch strings.ToLower(w) {
+		case "fabric", "forge", "neoforge", "quilt", "mc", "mod", "universal":
+			continue
+		}
+		if strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			continue
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+func zipSHA1(f *zip.File) (string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	sum, err := checksum.Reader(r)
+	return sum.SHA1, err
+}
+
+func fileSHA1(p string) (string, error) {
+	return checksum.FileWith(p, checksum.SHA1)
+}
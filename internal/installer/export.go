@@ -0,0 +1,305 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/events"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/mrpack"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/packwiz"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// @brief Mrpack builds a Modrinth modpack from the enabled manifest entries.
+// Modrinth and GitHub files are linked; local files, Maven artifacts and urls
+// launchers would refuse are embedded under overrides/. CurseForge files may
+// not be redistributed, so the export is refused while any are enabled.
+// @param ctx context for cancellation
+// @param name pack name
+// @param version pack version
+// @param loaderVersion loader version to record, empty = the manifest's
+// @return pack ready to write, or error
+func (ins *Installer) Mrpack(ctx context.Context, name, version, loaderVersion string) (*mrpack.Pack, error) {
+	mc := ins.man.Minecraft
+	deps := map[string]string{"minecraft": mc.Version}
+	if mc.Loader != "" && mc.Loader != "vanilla" {
+		key, ok := mrpack.LoaderKey(mc.Loader)
+		if !ok {
+			return nil, fmt.Errorf("mrpack has no %s loader; only fabric, quilt, forge and neoforge packs can be exported", mc.Loader)
+		}
+		if loaderVersion == "" {
+			loaderVersion = mc.LoaderVersion
+		}
+		if loaderVersion == "" || loaderVersion == "latest" {
+			return nil, fmt.Errorf("loader version is %q, pass a concrete one with --loader-version", loaderVersion)
+		}
+		deps[key] = loaderVersion
+	}
+
+	pack := &mrpack.Pack{
+		Index: mrpack.Index{
+			FormatVersion: 1,
+			Game:          "minecraft",
+			VersionID:     version,
+			Name:          name,
+			Files:         []mrpack.File{},
+			Dependencies:  deps,
+		},
+		Overrides: map[string]string{},
+		Embedded:  map[string]string{},
+	}
+	for _, folder := range []string{overrides.Common, overrides.Client, overrides.Server} {
+		dir := filepath.Join(ins.man.Dir(), folder)
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			pack.Overrides[folder] = dir
+		}
+	}
+
+	var restricted []string
+	for _, ct := range manifest.ContentTypes() {
+		for _, e := range ins.man.Entries(ct) {
+			if !e.Enable {
+				continue
+			}
+			if e.SourceName() == source.CurseForge {
+				restricted = append(restricted, e.Slug)
+				continue
+			}
+			if e.Version == "" {
+				return nil, fmt.Errorf("%s is not installed yet, run 'mod install' first", e.Slug)
+			}
+			dirs := manifest.Dirs(ins.gameDir, ct, e)
+			if len(dirs) == 0 {
+				ins.events.Event(events.Event{Kind: events.Skipped, Slug: e.Slug, Message: "no worlds found"})
+				continue
+			}
+
+			rel, err := ins.exportRelease(ctx, ct, e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", e.Slug, err)
+			}
+			if rel == nil || !mrpack.AllowedDownload(rel.URL) {
+				local, err := ins.localCopy(ct, e)
+				if err != nil {
+					return nil, err
+				}
+				for _, dir := range dirs {
+					pack.Embedded[ins.relPath(dir, e.Filename)] = local
+				}
+				continue
+			}
+
+			if rel.SHA1 == "" || rel.SHA512 == "" || rel.Size == 0 {
+				local, err := ins.localCopy(ct, e)
+				if err != nil {
+					return nil, err
+				}
+				sum, err := ins.man.Hashes().File(local)
+				if err != nil {
+					return nil, err
+				}
+				rel.SHA1, rel.SHA512 = sum.SHA1, sum.SHA512
+				fi, err := os.Stat(local)
+				if err != nil {
+					return nil, err
+				}
+				rel.Size = fi.Size()
+			}
+			for _, dir := range dirs {
+				pack.Index.Files = append(pack.Index.Files, mrpack.File{
+					Path:      ins.relPath(dir, e.Filename),
+					Hashes:    mrpack.Hashes{SHA1: rel.SHA1, SHA512: rel.SHA512},
+					Env:       envOf(e),
+					Downloads: []string{rel.URL},
+					FileSize:  rel.Size,
+				})
+			}
+		}
+	}
+	if len(restricted) > 0 {
+		return nil, fmt.Errorf("CurseForge does not allow redistributing %s; disable them or switch them to another source before exporting", strings.Join(restricted, ", "))
+	}
+	return pack, nil
+}
+
+// @brief exportRelease finds the remote file an installed entry can be linked to.
+// @param ctx context for cancellation
+// @param ct content type of the entry
+// @param e installed manifest entry
+// @return release, nil if the entry has to be embedded, or error
+func (ins *Installer) exportRelease(ctx context.Context, ct manifest.ContentType, e manifest.Entry) (*source.Release, error) {
+	switch e.Source {
+	case source.File, source.Maven:
+		return nil, nil
+	case source.URL:
+		rel, err := source.FromURL(e.URL, e.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		rel.SHA512 = e.SHA512
+		return rel, nil
+	case source.GitHub:
+		rel, err := ins.gh.Release(ctx, e.Repo, e.Version, e.Asset)
+		if err != nil {
+			return nil, err
+		}
+		rel.SHA1, rel.SHA512 = e.Checksum, e.SHA512
+		return rel, nil
+	default:
+		rel, err := ins.releaseForVersion(ctx, ct, e, e.Version)
+		if err != nil {
+			return nil, err
+		}
+		if e.Sums().Check(rel.Sums()) != nil {
+			return nil, fmt.Errorf("installed file does not match version %s, run 'mod install' first", e.VersionNumber)
+		}
+		return rel, nil
+	}
+}
+
+// @brief localCopy finds the installed file of an entry, enabled or not.
+// @param ct content type of the entry
+// @param e manifest entry
+// @return path to the file or error if it is not on disk
+func (ins *Installer) localCopy(ct manifest.ContentType, e manifest.Entry) (string, error) {
+	if e.Source == source.File {
+		rel, err := source.FromFile(ins.man.Dir(), e.Path)
+		if err != nil {
+			return "", err
+		}
+		return rel.Path, nil
+	}
+	for _, dir := range manifest.Dirs(ins.gameDir, ct, e) {
+		for _, name := range []string{e.Filename, e.Filename + ".disabled"} {
+			p := filepath.Join(dir, name)
+			if _, err := os.Stat(p); err == nil {
+				return p, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not on disk, run 'mod install' first", e.Slug)
+}
+
+// @brief relPath turns a destination folder and file name into a game-dir-relative slash path.
+func (ins *Installer) relPath(dir, name string) string {
+	rel, err := filepath.Rel(ins.gameDir, filepath.Join(dir, name))
+	if err != nil {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// @brief envOf converts recorded side support to the index's env block.
+// @return nil when nothing is known, which the format reads as required everywhere
+func envOf(e manifest.Entry) *mrpack.Env {
+	if e.ClientSide == "" && e.ServerSide == "" {
+		return nil
+	}
+	side := func(s string) string {
+		switch s {
+		case "optional", "unsupported":
+			return s
+		default:
+			return "required"
+		}
+	}
+	return &mrpack.Env{Client: side(e.ClientSide), Server: side(e.ServerSide)}
+}
+
+// @brief Packwiz builds a packwiz pack from the manifest, keeping every entry's version pin.
+// Disabled entries become optional files that are off by default. Files
+// without a download (local, Maven) are copied into the pack; overrides/
+// becomes plain pack files.
+// @param ctx context for cancellation
+// @param name pack name
+// @param version pack version
+// @return pack ready to write, or error
+func (ins *Installer) Packwiz(ctx context.Context, name, version string) (*packwiz.Pack, error) {
+	pack := &packwiz.Pack{
+		Meta:      packwiz.PackMeta{Name: name, Version: version},
+		Metafiles: map[string]packwiz.Metafile{},
+		Files:     map[string]string{},
+	}
+	if err := pack.SetMinecraft(ins.man.Minecraft); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(ins.man.Dir(), overrides.Common)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		pack.Files[filepath.ToSlash(rel)] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range []string{overrides.Client, overrides.Server} {
+		if _, err := os.Stat(filepath.Join(ins.man.Dir(), folder)); err == nil {
+			ins.events.Event(events.Event{Kind: events.Skipped, Slug: folder, Message: "packwiz has no side-specific files"})
+		}
+	}
+
+	for _, ct := range manifest.ContentTypes() {
+		for _, e := range ins.man.Entries(ct) {
+			if ct.PerWorld {
+				ins.events.Event(events.Event{Kind: events.Skipped, Slug: e.Slug, Message: "packwiz has no per-world content"})
+				continue
+			}
+			if e.Version == "" || e.Checksum == "" {
+				return nil, fmt.Errorf("%s is not installed yet, run 'mod install' first", e.Slug)
+			}
+			meta := packwiz.Metafile{Name: e.Slug, Filename: e.Filename, Side: packwiz.SideOf(e)}
+			if !e.Enable {
+				meta.Option = &packwiz.Option{Optional: true, Default: false}
+			}
+
+			if e.SourceName() == source.CurseForge {
+				pid, err1 := strconv.Atoi(projectID(e))
+				fid, err2 := strconv.Atoi(e.Version)
+				if err1 != nil || err2 != nil {
+					return nil, fmt.Errorf("%s: curseforge ids must be numeric", e.Slug)
+				}
+				meta.Download = packwiz.Download{Mode: "metadata:curseforge", HashFormat: "sha1", Hash: e.Checksum}
+				meta.Update = &packwiz.Update{CurseForge: &packwiz.CurseForgeUpdate{ProjectID: pid, FileID: fid}}
+				pack.Metafiles[packwiz.MetaPath(e)] = meta
+				continue
+			}
+
+			rel, err := ins.exportRelease(ctx, ct, e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", e.Slug, err)
+			}
+			if rel == nil {
+				local, err := ins.localCopy(ct, e)
+				if err != nil {
+					return nil, err
+				}
+				pack.Files[path.Join(filepath.ToSlash(e.Dest), e.Filename)] = local
+				continue
+			}
+			meta.Download = packwiz.Download{URL: rel.URL, HashFormat: "sha1", Hash: e.Checksum}
+			if e.SourceName() == source.Modrinth {
+				meta.Update = &packwiz.Update{Modrinth: &packwiz.ModrinthUpdate{ModID: projectID(e), Version: e.Version}}
+			}
+			pack.Metafiles[packwiz.MetaPath(e)] = meta
+		}
+	}
+	return pack, nil
+}
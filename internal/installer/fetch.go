@@ -0,0 +1,300 @@
+package installer
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/events"
+)
+
+const (
+	// stallTimeout aborts a download that has received nothing for this long.
+	stallTimeout = 30 * time.Second
+	// fetchAttempts is how often a broken download is resumed before giving up.
+	fetchAttempts = 4
+)
+
+var errStalled = fmt.Errorf("download stalled (no data for %s)", stallTimeout)
+
+// statusError is an HTTP status that retrying will not fix.
+type statusError struct {
+	url    string
+	status string
+	code   int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("GET %s: unexpected status %s", e.url, e.status)
+}
+
+// partMeta sits next to a partial download and tells whether it can be resumed.
+type partMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// @brief fetch downloads a URL into a partial file that survives failures, so
+// the next attempt (in this run or a later one) continues with a Range request.
+// @param ctx context for cancellation
+// @param slug what the file is for, in progress events
+// @param url file to download
+// @return path of the complete file and its hashes, or error
+func (ins *Installer) fetch(ctx context.Context, slug, url string) (string, checksum.Sums, error) {
+	part, unlock, err := ins.partialPath(url)
+	if err != nil {
+		return "", checksum.Sums{}, err
+	}
+	defer unlock()
+	var lastErr error
+	for attempt := 1; attempt <= fetchAttempts; attempt++ {
+		sum, err := ins.fetchOnce(ctx, slug, url, part)
+		if err == nil {
+			os.Remove(part + ".json")
+			done, err := moveAside(part)
+			return done, sum, err
+		}
+		lastErr = err
+		var se *statusError
+		if ctx.Err() != nil || (errors.As(err, &se) && se.code < 500) {
+			break
+		}
+		if attempt < fetchAttempts {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+	}
+	return "", checksum.Sums{}, lastErr
+}
+
+// @brief fetchOnce makes one request for the rest of a partial download.
+// The bytes already on disk are hashed first so the sums cover the whole file.
+// @param ctx context for cancellation
+// @param slug what the file is for, in progress events
+// @param url file to download
+// @param part partial file
+// @return hashes of the complete file, or error
+func (ins *Installer) fetchOnce(ctx context.Context, slug, url, part string) (checksum.Sums, error) {
+	meta := readPartMeta(part)
+	var off int64
+	if st, err := os.Stat(part); err == nil && meta.URL == url {
+		off = st.Size()
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stall := time.AfterFunc(stallTimeout, func() { cancel(errStalled) })
+	defer stall.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return checksum.Sums{}, err
+	}
+	if off > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", off))
+		if v := meta.validator(); v != "" {
+			req.Header.Set("If-Range", v)
+		}
+	}
+	res, err := ins.dl.Do(req)
+	if err != nil {
+		return checksum.Sums{}, cause(ctx, err)
+	}
+	defer res.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case res.StatusCode == http.StatusPartialContent && off > 0 && rangeStart(res.Header.Get("Content-Range")) == off:
+		flags |= os.O_APPEND
+	case res.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC // no range support, or the file changed
+		off = 0
+		meta = partMeta{URL: url, ETag: res.Header.Get("ETag"), LastModified: res.Header.Get("Last-Modified")}
+		if err := writePartMeta(part, meta); err != nil {
+			return checksum.Sums{}, err
+		}
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable, res.StatusCode == http.StatusPartialContent:
+		os.Remove(part) // start over on the next attempt
+		return checksum.Sums{}, fmt.Errorf("GET %s: cannot resume, restarting", url)
+	default:
+		return checksum.Sums{}, &statusError{url: url, status: res.Status, code: res.StatusCode}
+	}
+
+	h := checksum.NewWriter()
+	if off > 0 {
+		if err := hashPrefix(part, h); err != nil {
+			return checksum.Sums{}, err
+		}
+	}
+	f, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return checksum.Sums{}, err
+	}
+	var total int64
+	if res.ContentLength >= 0 {
+		total = off + res.ContentLength
+	}
+	body := &stallReader{r: res.Body, timer: stall}
+	prog := &progressWriter{sink: ins.events, slug: slug, n: off, total: total}
+	if _, err := io.Copy(io.MultiWriter(f, h, prog), body); err != nil {
+		f.Close()
+		return checksum.Sums{}, cause(ctx, err)
+	}
+	if err := f.Close(); err != nil {
+		return checksum.Sums{}, err
+	}
+	prog.report()
+	return h.Sums(), nil
+}
+
+// @brief partialPath returns where a URL's partial download lives: in the
+// download cache when there is one, else in the temp dir. The file is locked
+// for this download; while another install holds it, a fresh file of our own
+// is used instead, which is not resumed by later runs.
+// @param url file to download
+// @return partial file path and the function releasing it, or error
+func (ins *Installer) partialPath(url string) (string, func(), error) {
+	dir := filepath.Join(os.TempDir(), "modcli-partial")
+	if ins.cache != nil {
+		dir = ins.cache.Partial()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, err
+	}
+	sum := sha1.Sum([]byte(url))
+	name := hex.EncodeToString(sum[:])
+	part := filepath.Join(dir, name+".part")
+	if unlock, err := lockFile(part + ".lock"); err == nil {
+		return part, unlock, nil
+	}
+
+	f, err := os.CreateTemp(dir, name+"-*.part")
+	if err != nil {
+		return "", nil, err
+	}
+	f.Close()
+	return f.Name(), func() {
+		os.Remove(f.Name()) // gone already when the download finished
+		os.Remove(f.Name() + ".json")
+	}, nil
+}
+
+// @brief moveAside gives a finished download a name of its own, so the next
+// download of the same URL cannot truncate it before the caller is done.
+// @param part complete partial file
+// @return the new path, or error
+func moveAside(part string) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(part), "done-*")
+	if err != nil {
+		return "", err
+	}
+	f.Close()
+	if err := os.Rename(part, f.Name()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// validator is the If-Range value: a strong ETag, else Last-Modified.
+func (m partMeta) validator() string {
+	if m.ETag != "" && !strings.HasPrefix(m.ETag, "W/") {
+		return m.ETag
+	}
+	return m.LastModified
+}
+
+func readPartMeta(part string) partMeta {
+	var m partMeta
+	if b, err := os.ReadFile(part + ".json"); err == nil {
+		json.Unmarshal(b, &m)
+	}
+	return m
+}
+
+func writePartMeta(part string, m partMeta) error {
+	b, _ := json.Marshal(m)
+	return os.WriteFile(part+".json", b, 0o644)
+}
+
+// rangeStart parses the first byte of "bytes 100-199/200", or -1.
+func rangeStart(cr string) int64 {
+	cr = strings.TrimPrefix(cr, "bytes ")
+	start, _, ok := strings.Cut(cr, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func hashPrefix(path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// cause reports a stall instead of the "context canceled" it shows up as.
+func cause(ctx context.Context, err error) error {
+	if c := context.Cause(ctx); c != nil && !errors.Is(c, context.Canceled) {
+		return c
+	}
+	return err
+}
+
+// stallReader pushes the stall deadline back whenever data arrives.
+type stallReader struct {
+	r     io.Reader
+	timer *time.Timer
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if n > 0 {
+		s.timer.Reset(stallTimeout)
+	}
+	return n, err
+}
+
+// progressWriter turns written bytes into progress events, a few per second.
+type progressWriter struct {
+	sink  events.Sink
+	slug  string
+	n     int64
+	total int64
+	last  time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.n += int64(len(b))
+	if time.Since(p.last) >= 100*time.Millisecond {
+		p.report()
+	}
+	return len(b), nil
+}
+
+func (p *progressWriter) report() {
+	p.last = time.Now()
+	p.sink.Event(events.Event{Kind: events.Progress, Slug: p.slug, Bytes: p.n, Total: p.total})
+}
@@ -0,0 +1,124 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/events"
+)
+
+func TestRangeStart(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"bytes 100-199/200", 100},
+		{"bytes 0-0/1", 0},
+		{"bytes */200", -1},
+		{"", -1},
+		{"bytes x-1/2", -1},
+	}
+	for _, tt := range tests {
+		if got := rangeStart(tt.in); got != tt.want {
+			t.Errorf("rangeStart(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFetchOnceResume(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789"), 1000)
+	const etag = `"v1"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "file.jar", time.Time{}, bytes.NewReader(body))
+	}))
+	defer srv.Close()
+	url := srv.URL + "/file.jar"
+	want, err := checksum.Reader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		prefix  []byte // already on disk
+		etag    string // recorded for the partial file
+		wantErr bool
+	}{
+		{name: "206 appends", prefix: body[:4000], etag: etag},
+		{name: "200 restarts when the file changed", prefix: []byte("stale bytes"), etag: `"v0"`},
+		{name: "416 drops the partial file", prefix: append(append([]byte{}, body...), "extra"...), etag: etag, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part := filepath.Join(t.TempDir(), "x.part")
+			if err := os.WriteFile(part, tt.prefix, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := writePartMeta(part, partMeta{URL: url, ETag: tt.etag}); err != nil {
+				t.Fatal(err)
+			}
+			ins := &Installer{dl: srv.Client(), events: events.Discard{}}
+
+			sum, err := ins.fetchOnce(context.Background(), "x", url, part)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if _, err := os.Stat(part); !os.IsNotExist(err) {
+					t.Errorf("partial file kept: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sum != want {
+				t.Errorf("sums = %v, want %v", sum, want)
+			}
+			got, err := os.ReadFile(part)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, body) {
+				t.Errorf("file has %d bytes, want the %d served", len(got), len(body))
+			}
+		})
+	}
+}
+
+func TestPartialPathLocked(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ins := &Installer{}
+	const url = "https://cdn.example/file.jar"
+
+	first, unlock, err := ins.partialPath(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, unlock2, err := ins.partialPath(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Fatalf("a held partial file was handed out twice: %s", first)
+	}
+	unlock2()
+	unlock()
+
+	again, unlock, err := ins.partialPath(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	if again != first {
+		t.Errorf("after unlocking got %s, want the shared %s", again, first)
+	}
+}
@@ -2,20 +2,26 @@ package installer
 
 import (
 	"context"
-	"crypto/sha1"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/silask7188/ModrinthCLI/internal/cache"
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/config"
+	"github.com/silask7188/ModrinthCLI/internal/curseforge"
+	"github.com/silask7188/ModrinthCLI/internal/events"
 	"github.com/silask7188/ModrinthCLI/internal/manifest"
 	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/source"
 )
 
 /*
@@ -28,9 +34,22 @@ import (
 type Installer struct {
 	gameDir string
 	man     *manifest.Manifest
-	api     *modrinth.Client
-	http    *http.Client
+	sources map[string]source.Provider // by Entry.SourceName()
+	missing map[string]error           // providers that are not configured, and why
+	mr      *modrinth.Client           // Modrinth-only lookups (hashes, modpacks)
+	cf      *curseforge.Client         // CurseForge-only lookups (pack files), nil when not configured
+	gh      *source.GitHubClient
+	maven   *source.MavenClient
+	http    *http.Client // API lookups
+	dl      *http.Client // downloads, bounded by stallTimeout instead of a total timeout
+	cache   *cache.Cache // shared download cache, nil when disabled
+	nocache error        // why the cache is disabled, warned about by the next Install
+	seen    sync.Once    // registers the manifest with the cache
+	events  events.Sink
 	concur  int // worker count
+
+	mu    sync.Mutex // guards manifest entries while Install's workers run
+	dirty bool       // an entry changed, Install saves the manifest once it is done
 }
 
 // @brief New creates a new Installer instance.
@@ -42,17 +61,51 @@ func New(gameDir string, man *manifest.Manifest) (*Installer, error) {
 	if err != nil {
 		return nil, err
 	}
+	gh, err := source.NewGitHub("https://api.github.com/")
+	if err != nil {
+		return nil, err
+	}
+	sources := map[string]source.Provider{source.Modrinth: api}
+	missing := map[string]error{}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cf, err := curseforge.FromConfig(cfg)
+	if err == nil {
+		sources[source.CurseForge] = cf
+	} else {
+		missing[source.CurseForge] = err
+	}
+	store, cacheErr := cache.FromConfig(cfg)
+
 	return &Installer{
 		gameDir: gameDir,
 		man:     man,
-		api:     api,
+		sources: sources,
+		missing: missing,
+		mr:      api,
+		cf:      cf,
+		gh:      gh,
+		maven:   source.NewMaven(),
+		cache:   store,
+		nocache: cacheErr,
 		http: &http.Client{
 			Timeout: 45 * time.Second,
 		},
+		dl:     &http.Client{},
+		events: events.NewPlain(os.Stdout),
 		concur: 4, // default – can expose flag later
 	}, nil
 }
 
+// @brief SetEvents chooses where progress and results are reported.
+// @param s sink; the default logs plain lines to stdout
+func (ins *Installer) SetEvents(s events.Sink) {
+	ins.events = s
+}
+
 /*
 --------------------------------------------------
   PUBLIC ENTRY-POINTS
@@ -60,32 +113,150 @@ func New(gameDir string, man *manifest.Manifest) (*Installer, error) {
 */
 
 // @brief Install downloads and installs all enabled mods.
+// By default every entry is tried and the report says how each one went;
+// with failFast the first failure cancels the rest and is returned.
 // @param ctx context for cancellation
-// @return error if any
-func (ins *Installer) Install(ctx context.Context) error {
-	sections := []*[]manifest.Entry{
-		&ins.man.Mods,
-		&ins.man.ResourcePacks,
-		&ins.man.Shaders,
+// @param failFast stop at the first failed entry
+// @return per-entry results, and an error if the install could not run or
+// failFast stopped it (failed entries alone are only in the report)
+func (ins *Installer) Install(ctx context.Context, failFast bool) (*Report, error) {
+	if ins.nocache != nil {
+		ins.events.Event(events.Event{Kind: events.Warning, Message: fmt.Sprintf("download cache disabled: %v", ins.nocache)})
+		ins.nocache = nil
+	}
+	side := ins.man.TargetSide()
+	if err := ins.fillSides(ctx); err != nil {
+		return nil, err
 	}
 
-	grp, ctx := errgroup.WithContext(ctx)
+	rep := &Report{}
+	var mu sync.Mutex
+	add := func(r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		rep.Results = append(rep.Results, r)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	grp := &errgroup.Group{}
+	gctx := ctx
+	if failFast {
+		grp, gctx = errgroup.WithContext(ctx)
+	}
 	grp.SetLimit(ins.concur)
 
-	for _, section := range sections {
-		for i := range *section {
-			ent := &(*section)[i]
+	n := 0
+	for _, ct := range manifest.ContentTypes() {
+		entries := ins.man.Entries(ct)
+		for i := range entries {
+			ent := &entries[i]
+			order := n
+			n++
+			res := Result{Slug: ent.Slug, Type: ct.Name, Version: ent.VersionNumber, order: order}
 			if !ent.Enable {
+				res.Outcome = Disabled
+				add(res)
 				continue
 			}
-			grp.Go(func(ent *manifest.Entry) func() error {
-				return func() error {
-					return ins.installOne(ctx, ent)
+			if !ent.SupportsSide(side) {
+				if err := ins.skipUnsupported(ct, ent, side); err != nil {
+					if failFast {
+						cancel() // stop the downloads already started
+						_ = grp.Wait()
+						rep.sort()
+						if serr := ins.saveChanges(); serr != nil {
+							return rep, serr
+						}
+						return rep, err
+					}
+					res.Outcome, res.Reason = Failed, err.Error()
+				} else {
+					res.Outcome, res.Reason = Skipped, "unsupported on "+side
+				}
+				add(res)
+				continue
+			}
+			grp.Go(func() error {
+				out, err := ins.installOne(gctx, ct, ent)
+				if err != nil {
+					if failFast && gctx.Err() != nil {
+						return err // cancelled by another entry's failure
+					}
+					ins.events.Event(events.Event{Kind: events.Failed, Slug: ent.Slug, Message: err.Error()})
+					out = res
+					out.Outcome, out.Reason = Failed, err.Error()
+				}
+				out.order = order
+				add(out)
+				if failFast {
+					return err
 				}
-			}(ent))
+				return nil
+			})
 		}
 	}
-	return grp.Wait()
+	err := grp.Wait()
+	rep.sort()
+	if serr := ins.saveChanges(); serr != nil {
+		return rep, serr
+	}
+	if err != nil {
+		return rep, err
+	}
+	return rep, ins.ApplyOverrides(false)
+}
+
+// @brief update changes an entry while other workers may be running and
+// marks the manifest for saving.
+// @param e entry to change
+// @param fn applies the change
+func (ins *Installer) update(e *manifest.Entry, fn func(e *manifest.Entry)) {
+	ins.mu.Lock()
+	defer ins.mu.Unlock()
+	fn(e)
+	ins.dirty = true
+}
+
+// @brief saveChanges writes the manifest if an entry changed, and the hash
+// index either way. Called once every worker is done.
+// @return error if saving failed
+func (ins *Installer) saveChanges() error {
+	ins.mu.Lock()
+	defer ins.mu.Unlock()
+	if !ins.dirty {
+		if err := ins.man.Hashes().Save(); err != nil {
+			return fmt.Errorf("failed to save hash index: %w", err)
+		}
+		return nil
+	}
+	if err := ins.man.Save(); err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
+	ins.dirty = false
+	return nil
+}
+
+// @brief ApplyOverrides copies the pack's override files into the game dir.
+// Files edited locally since they were placed are reported and left alone.
+// @param force overwrite locally edited files as well
+// @return error if copying failed
+func (ins *Installer) ApplyOverrides(force bool) error {
+	dirs := overrides.Dirs(ins.man.Dir(), ins.man.TargetSide())
+	if len(dirs) == 0 {
+		return nil
+	}
+	res, err := overrides.Apply(dirs, ins.gameDir, force)
+	if err != nil {
+		return err
+	}
+	for _, rel := range res.Placed {
+		ins.events.Event(events.Event{Kind: events.Override, Dest: rel})
+	}
+	for _, rel := range res.Conflicts {
+		ins.events.Event(events.Event{Kind: events.Warning, Message: fmt.Sprintf("override %s skipped: edited locally (use 'mod overrides apply --force' to replace)", rel)})
+	}
+	return nil
 }
 
 // Update record – used by PlanUpdates().
@@ -93,25 +264,37 @@ type Update struct {
 	Entry          manifest.Entry
 	CurrentVersion string
 	TargetVersion  string
+	Err            error // the lookup failed (only without failFast)
 }
 
 // @brief PlanUpdates checks for updates to enabled mods.
 // @param ctx context for cancellation
+// @param failFast return the first failed lookup instead of recording it in Update.Err
 // @return slice of Update records or error
-func (ins *Installer) PlanUpdates(ctx context.Context) ([]Update, error) {
+func (ins *Installer) PlanUpdates(ctx context.Context, failFast bool) ([]Update, error) {
 	var out []Update
-	for i, e := range ins.man.Enabled() {
-		latest, err := ins.resolveVersion(ctx, e)
-		if err != nil {
-			return nil, err
-		}
-		cur := e.Version
-		if cur != latest {
-			out = append(out, Update{
-				Entry:          ins.man.Enabled()[i],
-				CurrentVersion: cur,
-				TargetVersion:  latest,
-			})
+	side := ins.man.TargetSide()
+	for _, ct := range manifest.ContentTypes() {
+		for _, e := range ins.man.Entries(ct) {
+			if !e.Enable || !e.SupportsSide(side) {
+				continue
+			}
+			latest, err := ins.latestVersion(ctx, ct, e)
+			if err != nil {
+				if failFast {
+					return nil, err
+				}
+				out = append(out, Update{Entry: e, CurrentVersion: e.Version, Err: err})
+				continue
+			}
+			cur := e.Version
+			if cur != latest {
+				out = append(out, Update{
+					Entry:          e,
+					CurrentVersion: cur,
+					TargetVersion:  latest,
+				})
+			}
 		}
 	}
 	return out, nil
@@ -125,100 +308,233 @@ func (ins *Installer) PlanUpdates(ctx context.Context) ([]Update, error) {
 
 // @brief installOne resolves the version, downloads the file, and places it in the game directory.
 // @param ctx context for cancellation
+// @param ct content type of the entry
 // @param e manifest entry to install
-// @return error if any
-func (ins *Installer) installOne(ctx context.Context, e *manifest.Entry) error {
-	verID, err := ins.resolveVersion(ctx, *e)
-	if err != nil {
-		return err
+// @return what happened, or error
+func (ins *Installer) installOne(ctx context.Context, ct manifest.ContentType, e *manifest.Entry) (Result, error) {
+	res := Result{Slug: e.Slug, Type: ct.Name}
+	if err := ins.restoreSkipped(ct, e); err != nil {
+		return res, err
 	}
-
-	file, sha1sum, err := ins.fileForVersion(ctx, verID)
+	ins.events.Event(events.Event{Kind: events.Resolve, Slug: e.Slug})
+	rel, err := ins.resolve(ctx, ct, *e)
 	if err != nil {
-		return err
+		return res, err
 	}
+	res.Version = rel.VersionNumber
 
-	destDir := filepath.Join(ins.gameDir, e.Dest)
-	if err = os.MkdirAll(destDir, 0o755); err != nil {
-		return err
+	dirs := manifest.Dirs(ins.gameDir, ct, *e)
+	if len(dirs) == 0 {
+		ins.events.Event(events.Event{Kind: events.Skipped, Slug: e.Slug, Message: "no worlds found"})
+		res.Outcome, res.Reason = Skipped, "no worlds found"
+		return res, nil
 	}
 
-	destPath := filepath.Join(destDir, file.Filename)
+	// collect the folders that still need the file
+	var missing []string
+	healed := false
+	for _, destDir := range dirs {
+		if err = os.MkdirAll(destDir, 0o755); err != nil {
+			return res, err
+		}
+		if rel.Sums().Empty() {
+			missing = append(missing, destDir) // hash unknown until fetched
+			continue
+		}
+		destPath := filepath.Join(destDir, rel.Filename)
 
-	// check if the expected file is already present and valid
-	if ok, _ := fileExistsWithSHA1(destPath, sha1sum); ok {
-		return nil
-	}
+		// check if the expected file is already present and valid
+		if ins.verify(*e, destPath, rel) == nil {
+			continue
+		}
+		// or the same file under the name an earlier run recorded
+		if e.Filename != "" && e.Filename != rel.Filename && ins.verify(*e, filepath.Join(destDir, e.Filename), rel) == nil {
+			continue
+		}
 
-	// fallback: search for any file with the correct checksum
-	if found, err := findFileByChecksum(destDir, sha1sum); err == nil {
-		// found correct file under a different name — update manifest
-		e.Filename = found
-		e.Checksum = sha1sum
-		e.Version = verID
-		_ = ins.man.Save() // silently save fix
-		return nil
+		// fallback: search for any file with the correct checksum
+		if found, err := ins.man.Hashes().Find(destDir, rel.Sums(), ct.Accepts); err == nil {
+			// found correct file under a different name — update manifest
+			sum, err := ins.man.Hashes().File(filepath.Join(destDir, found))
+			if err != nil {
+				return res, err
+			}
+			ins.update(e, func(e *manifest.Entry) {
+				e.Filename = found
+				e.Checksum, e.SHA512 = sum.SHA1, sum.SHA512
+				e.Version = rel.Version
+			})
+			healed = true
+			continue
+		}
+		missing = append(missing, destDir)
+	}
+	if len(missing) == 0 {
+		ins.events.Event(events.Event{Kind: events.Present, Slug: e.Slug, Version: rel.VersionNumber})
+		res.Outcome = Present
+		if healed {
+			res.Outcome = Healed
+		}
+		return res, nil
 	}
 
 	// file not found download
-	tmp, err := ins.download(ctx, file.URL, sha1sum)
+	tmp, sum, err := ins.download(ctx, e.Slug, rel)
 	if err != nil {
-		return err
+		return res, err
 	}
-	defer os.Remove(tmp)
+	defer ins.discard(tmp)
 
-	if err = backupIfExists(destPath); err != nil {
-		return err
+	var placed []string
+	for _, destDir := range missing {
+		destPath := filepath.Join(destDir, rel.Filename)
+		if ok, _ := ins.man.Hashes().Matches(destPath, sum); ok {
+			continue
+		}
+		if err = backupIfExists(destPath); err != nil {
+			return res, err
+		}
+		if err = cache.Place(tmp, destPath); err != nil {
+			return res, fmt.Errorf("failed to move file to final destination: %w", err)
+		}
+		ins.man.Hashes().Record(destPath, sum)
+		placed = append(placed, destDir)
 	}
 
-	if err = os.Rename(tmp, destPath); err != nil {
-		return fmt.Errorf("failed to move file to final destination: %w", err)
+	// record updated manifest data, saved once every entry is done
+	ins.update(e, func(e *manifest.Entry) {
+		e.Checksum, e.SHA512 = sum.SHA1, sum.SHA512
+		e.Filename = rel.Filename
+		e.Version = rel.Version
+		e.VersionNumber = rel.VersionNumber
+	})
+
+	for _, destDir := range placed {
+		where, _ := filepath.Rel(ins.gameDir, destDir)
+		ins.events.Event(events.Event{Kind: events.Placed, Slug: e.Slug, Version: e.VersionNumber, Dest: where})
 	}
+	res.Outcome = Installed
+	if len(placed) == 0 {
+		ins.events.Event(events.Event{Kind: events.Present, Slug: e.Slug, Version: rel.VersionNumber})
+		res.Outcome = Present
+	}
+	return res, nil
+}
 
-	// record updated manifest data
-	e.Checksum = sha1sum
-	e.Filename = file.Filename
-	e.Version = verID
-	if err := ins.man.Save(); err != nil {
-		return err
+// @brief fillSides records client/server support for entries added before it was tracked.
+// An entry whose project cannot be looked up is left unknown, which installs
+// it on both sides, and a warning is reported.
+// @param ctx context for cancellation
+// @return error if cancelled or the manifest could not be saved
+func (ins *Installer) fillSides(ctx context.Context) error {
+	changed := false
+	for _, ct := range manifest.ContentTypes() {
+		entries := ins.man.Entries(ct)
+		for i := range entries {
+			ent := &entries[i]
+			if !ent.Enable || ent.SourceName() != source.Modrinth || ent.ClientSide != "" || ent.ServerSide != "" {
+				continue
+			}
+			prj, err := ins.sources[source.Modrinth].LookupProject(ctx, ent.Slug)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				ins.events.Event(events.Event{Kind: events.Warning, Slug: ent.Slug, Message: fmt.Sprintf("sides unknown, installing on both: %v", err)})
+				continue
+			}
+			ent.ClientSide = prj.ClientSide
+			ent.ServerSide = prj.ServerSide
+			changed = true
+		}
+	}
+	if changed {
+		return ins.man.Save()
 	}
+	return nil
+}
 
-	fmt.Printf("[+] %s -> %s (%s)\n", e.Slug, e.Dest, e.VersionNumber)
+// @brief skipUnsupported leaves an entry out of this side's install, disabling a stale copy on disk.
+// @param ct content type of the entry
+// @param e manifest entry that does not run on this side
+// @param side the side being installed
+// @return error if an existing file could not be disabled
+func (ins *Installer) skipUnsupported(ct manifest.ContentType, e *manifest.Entry, side string) error {
+	for _, dir := range manifest.Dirs(ins.gameDir, ct, *e) {
+		if e.Filename == "" {
+			break
+		}
+		path := filepath.Join(dir, e.Filename)
+		if _, err := os.Stat(path); err == nil {
+			if err := os.Rename(path, path+".disabled"); err != nil {
+				return fmt.Errorf("failed to disable %s: %w", e.Slug, err)
+			}
+		}
+	}
+	ins.events.Event(events.Event{Kind: events.Skipped, Slug: e.Slug, Message: "unsupported on " + side})
 	return nil
 }
 
+// @brief restoreSkipped renames back a copy skipUnsupported disabled, now that
+// the entry runs on this side again. Enabled entries are never disabled on
+// disk otherwise.
+// @param ct content type of the entry
+// @param e enabled manifest entry supported on this side
+// @return error if the file could not be renamed
+func (ins *Installer) restoreSkipped(ct manifest.ContentType, e *manifest.Entry) error {
+	if e.Filename == "" {
+		return nil
+	}
+	for _, dir := range manifest.Dirs(ins.gameDir, ct, *e) {
+		path := filepath.Join(dir, e.Filename)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if _, err := os.Stat(path + ".disabled"); err != nil {
+			continue
+		}
+		if err := os.Rename(path+".disabled", path); err != nil {
+			return fmt.Errorf("failed to enable %s again: %w", e.Slug, err)
+		}
+	}
+	return nil
+}
+
+// @brief provider returns the provider an entry is hosted on.
+// @param e manifest entry
+// @return provider or error if the source is unknown or not configured
+func (ins *Installer) provider(e manifest.Entry) (source.Provider, error) {
+	if p, ok := ins.sources[e.SourceName()]; ok {
+		return p, nil
+	}
+	if err, ok := ins.missing[e.SourceName()]; ok {
+		return nil, fmt.Errorf("%s: %w", e.Slug, err)
+	}
+	return nil, fmt.Errorf("unknown source %q for %s", e.Source, e.Slug)
+}
+
+// @brief projectID returns the id to query an entry's provider with.
+func projectID(e manifest.Entry) string {
+	if e.ProjectID != "" {
+		return e.ProjectID
+	}
+	return e.Slug
+}
+
 // @brief resolveVersion fetches the latest compatible version ID for a given entry.
 // @param ctx context for cancellation
+// @param ct content type of the entry, decides the loader filter
 // @param e manifest entry to resolve
 // @return version ID or error
-func (ins *Installer) resolveVersion(ctx context.Context, e manifest.Entry) (string, error) {
-	var vers []modrinth.Version
-	var err error
-	switch e.Dest {
-	case "mods":
-		vers, err = ins.api.ProjectVersions(
-			ctx,
-			e.Slug,
-			ins.man.Minecraft.Version,
-			ins.man.Minecraft.Loader,
-		)
-	case "resourcepacks":
-		vers, err = ins.api.ProjectVersions(
-			ctx,
-			e.Slug,
-			ins.man.Minecraft.Version,
-			"",
-		)
-	case "shaderpacks":
-		vers, err = ins.api.ProjectVersions(
-			ctx,
-			e.Slug,
-			ins.man.Minecraft.Version,
-			"",
-		)
-	default:
-		return "", fmt.Errorf("unknown type %q for %s", e.Dest, e.Slug)
+func (ins *Installer) resolveVersion(ctx context.Context, ct manifest.ContentType, e manifest.Entry) (string, error) {
+	if (e.Modpack || e.Pinned) && e.Version != "" {
+		return e.Version, nil // the modpack or the user decides
+	}
+	p, err := ins.provider(e)
+	if err != nil {
+		return "", err
 	}
+	vers, err := ct.Versions(ctx, p, projectID(e), ins.man.Minecraft)
 	if err != nil {
 		return "", err
 	}
@@ -227,27 +543,130 @@ func (ins *Installer) resolveVersion(ctx context.Context, e manifest.Entry) (str
 	}
 	// ensure newest first by published date
 	sort.Slice(vers, func(i, j int) bool {
-		return vers[i].DatePublished > vers[j].DatePublished
+		return vers[i].Published > vers[j].Published
 	})
 	return vers[0].ID, nil
 }
 
-// @brief fileForVersion fetches the primary file for a given version ID.
+// @brief releaseForVersion fetches the primary file for a given version ID.
+// A file named like the entry's recorded one wins, otherwise the first file
+// with an extension the content type accepts.
 // @param ctx context for cancellation
+// @param ct content type of the entry
+// @param e manifest entry
 // @param verID version ID to fetch
-// @return File instance and its SHA1 hash, or error
-func (ins *Installer) fileForVersion(ctx context.Context, verID string) (*modrinth.File, string, error) {
-	v, err := ins.api.Version(ctx, verID)
+// @return release describing the file, or error
+func (ins *Installer) releaseForVersion(ctx context.Context, ct manifest.ContentType, e manifest.Entry, verID string) (*source.Release, error) {
+	p, err := ins.provider(e)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.ResolveFiles(ctx, projectID(e), verID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("version %s has no files", verID)
+	}
+	for i := range files {
+		if e.Filename != "" && files[i].Filename == e.Filename {
+			return &files[i], nil
+		}
+	}
+	for i := range files {
+		if ct.Accepts(files[i].Filename) {
+			return &files[i], nil
+		}
+	}
+	return &files[0], nil
+}
+
+// @brief resolve finds the file an entry should have installed, whatever its source.
+// @param ctx context for cancellation
+// @param ct content type of the entry
+// @param e manifest entry to resolve
+// @return release to install or error
+func (ins *Installer) resolve(ctx context.Context, ct manifest.ContentType, e manifest.Entry) (*source.Release, error) {
+	switch e.Source {
+	case source.URL:
+		rel, err := source.FromURL(e.URL, e.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		rel.SHA512 = e.SHA512
+		return rel, nil
+	case source.File:
+		return source.FromFile(ins.man.Dir(), e.Path)
+	case source.GitHub:
+		rel, err := ins.gh.Release(ctx, e.Repo, e.Tag, e.Asset)
+		if err != nil {
+			return nil, err
+		}
+		return pinSums(rel, e), nil
+	case source.Maven:
+		rel, err := ins.maven.Release(ctx, e.Repository, e.Maven)
+		if err != nil {
+			return nil, err
+		}
+		return pinSums(rel, e), nil
+	default:
+		verID, err := ins.resolveVersion(ctx, ct, e)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := ins.releaseForVersion(ctx, ct, e, verID)
+		if err != nil {
+			return nil, err
+		}
+		return pinSums(rel, e), nil
 	}
-	for _, f := range v.Files {
-		if f.Primary {
-			return &f, f.Hashes.SHA1, nil
+}
+
+// @brief pinSums fills in the hashes recorded on first install when the
+// source publishes none (GitHub assets, Maven artifacts without sidecars,
+// CurseForge files without a SHA1), so an unchanged file counts as present.
+// @param rel release from the source
+// @param e manifest entry
+// @return rel
+func pinSums(rel *source.Release, e manifest.Entry) *source.Release {
+	if rel.Sums().Empty() && rel.Version == e.Version && rel.Filename == e.Filename {
+		rel.SHA1, rel.SHA512 = e.Checksum, e.SHA512
+	}
+	return rel
+}
+
+// @brief latestVersion returns the version an entry would update to.
+// Cheaper than resolve for provider entries, which skip the file lookup.
+// @param ctx context for cancellation
+// @param ct content type of the entry
+// @param e manifest entry
+// @return version id or error
+func (ins *Installer) latestVersion(ctx context.Context, ct manifest.ContentType, e manifest.Entry) (string, error) {
+	switch e.Source {
+	case source.URL, source.File, source.GitHub, source.Maven:
+		rel, err := ins.resolve(ctx, ct, e)
+		if err != nil {
+			return "", err
 		}
+		return rel.Version, nil
+	default:
+		return ins.resolveVersion(ctx, ct, e)
 	}
-	f := v.Files[0]
-	return &f, f.Hashes.SHA1, nil
+}
+
+// @brief verify checks a file on disk against a release, through the entry's provider if it has one.
+// @param e manifest entry
+// @param path file to check
+// @param rel expected release
+// @return nil if the file is there and matches
+func (ins *Installer) verify(e manifest.Entry, path string, rel *source.Release) error {
+	if ok, _ := ins.man.Hashes().Matches(path, rel.Sums()); ok {
+		return nil // unchanged since it was last verified
+	}
+	if p, ok := ins.sources[e.SourceName()]; ok {
+		return p.VerifyFile(path, rel)
+	}
+	return source.VerifyHashes(path, rel)
 }
 
 /*
@@ -256,59 +675,90 @@ func (ins *Installer) fileForVersion(ctx context.Context, verID string) (*modrin
 --------------------------------------------------
 */
 
-// @brief download fetches a file from the given URL and verifies its SHA256 hash.
+// @brief download fetches a release and verifies its SHA1/SHA512 hashes.
+// Releases already in the download cache are not fetched again, and new
+// downloads are kept there. Broken downloads resume where they stopped (see
+// fetch). Local file releases are copied instead.
 // @param ctx context for cancellation
-// @param url URL to download from
-// @param wantSHA expected SHA256 hash of the file
-// @return path to the downloaded file or error
-func (ins *Installer) download(ctx context.Context, url, wantSHA string) (string, error) {
+// @param slug what the file is for, in progress events
+// @param rel release to fetch; empty hashes skip verification
+// @return path to the file (release it with discard) and its hashes, or error
+func (ins *Installer) download(ctx context.Context, slug string, rel *source.Release) (string, checksum.Sums, error) {
+	want := rel.Sums()
+	if rel.Path == "" {
+		if p, sum, ok := ins.cache.Lookup(want); ok {
+			ins.register()
+			ins.events.Event(events.Event{Kind: events.Verified, Slug: slug, Version: rel.VersionNumber})
+			return p, sum, nil
+		}
+	}
 
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	res, err := ins.http.Do(req)
+	var tmp string
+	var got checksum.Sums
+	var err error
+	from := rel.URL
+	if rel.Path != "" {
+		from = rel.Path
+		tmp, got, err = copyTemp(rel.Path)
+	} else {
+		tmp, got, err = ins.fetch(ctx, slug, rel.URL)
+	}
 	if err != nil {
-		return "", err
+		return "", checksum.Sums{}, err
+	}
+	if !want.Empty() {
+		if err := want.Check(got); err != nil {
+			os.Remove(tmp)
+			return "", checksum.Sums{}, fmt.Errorf("%w for %s", err, from)
+		}
+	}
+	ins.events.Event(events.Event{Kind: events.Verified, Slug: slug, Version: rel.VersionNumber})
+	if rel.Path == "" && ins.cache != nil {
+		if p, err := ins.cache.Put(tmp, got); err == nil {
+			ins.register()
+			return p, got, nil
+		}
 	}
-	defer res.Body.Close()
+	return tmp, got, nil
+}
 
+// @brief copyTemp copies a local file into a temp file, hashing it on the way.
+// @param src file to copy
+// @return temp file path and its hashes, or error
+func copyTemp(src string) (string, checksum.Sums, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", checksum.Sums{}, err
+	}
+	defer in.Close()
 	tmp, err := os.CreateTemp("", "mr-*")
 	if err != nil {
-		return "", err
+		return "", checksum.Sums{}, err
 	}
 	defer tmp.Close()
 
-	h := sha1.New()
-	if _, err = io.Copy(io.MultiWriter(tmp, h), res.Body); err != nil {
-		return "", err
-	}
-	var got string
-	if got = hex.EncodeToString(h.Sum(nil)); got != wantSHA {
-		return "", fmt.Errorf("sha1 mismatch for %s (want %s, got %s)", url, wantSHA, got)
+	h := checksum.NewWriter()
+	if _, err = io.Copy(io.MultiWriter(tmp, h), in); err != nil {
+		os.Remove(tmp.Name())
+		return "", checksum.Sums{}, err
 	}
-	// fmt.Println("Downloading:", url)
-	// fmt.Println("Expecting SHA-1:", wantSHA)
-	// fmt.Println("Actual SHA-1:", got)
-	return tmp.Name(), nil
+	return tmp.Name(), h.Sums(), nil
 }
 
-// @brief fileExistsWithSHA1 checks if a file exists and matches the given SHA1 hash.
-// @param path file path to check
-// @param want expected SHA1 hash
-// @return true if file exists and matches, false if not, or error if any
-func fileExistsWithSHA1(path, want string) (bool, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
-		}
-		return false, err
+// @brief discard removes a file returned by download, unless the cache holds it.
+func (ins *Installer) discard(path string) {
+	if !ins.cache.Holds(path) {
+		os.Remove(path)
 	}
-	defer f.Close()
+}
 
-	h := sha1.New()
-	if _, err = io.Copy(h, f); err != nil {
-		return false, err
-	}
-	return hex.EncodeToString(h.Sum(nil)) == want, nil
+// @brief register records the manifest with the cache, so gc keeps its files.
+func (ins *Installer) register() {
+	ins.seen.Do(func() {
+		if ins.man.Path() != "" {
+			ins.cache.Register(ins.man.Path())
+		}
+	})
 }
 
 func backupIfExists(path string) error {
@@ -318,25 +768,3 @@ func backupIfExists(path string) error {
 	}
 	return nil
 }
-
-func findFileByChecksum(dir, wantHash string) (string, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return "", err
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		path := filepath.Join(dir, entry.Name())
-		ok, err := fileExistsWithSHA1(path, wantHash)
-		if err != nil {
-			return "", err
-		}
-		if ok {
-			return entry.Name(), nil
-		}
-	}
-	return "", fmt.Errorf("no file with checksum %s found in %s", wantHash, dir)
-}
@@ -0,0 +1,238 @@
+package installer
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/silask7188/ModrinthCLI/internal/events"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// recorder is a sink that keeps every event.
+type recorder struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (r *recorder) Event(ev events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+// @brief has reports whether an entry got an event of a kind.
+func (r *recorder) has(slug string, k events.Kind) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ev := range r.events {
+		if ev.Slug == slug && ev.Kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// testInstall is a game dir with a manifest of url entries served by srv.
+type testInstall struct {
+	srv  *httptest.Server
+	game string
+	man  *manifest.Manifest
+	mods manifest.ContentType
+}
+
+func newTestInstall(t *testing.T) *testInstall {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir()) // partial downloads
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone.jar" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	t.Cleanup(srv.Close)
+	game := t.TempDir()
+	mods, _ := manifest.TypeForSection("mods")
+	return &testInstall{
+		srv:  srv,
+		game: game,
+		man:  manifest.New(filepath.Join(game, "project.json"), manifest.Minecraft{Version: "1.20.1", Loader: "fabric"}),
+		mods: mods,
+	}
+}
+
+// @brief body returns what the server sends for a file and its sha1.
+func body(name string) ([]byte, string) {
+	b := []byte("content of /" + name)
+	sum := sha1.Sum(b)
+	return b, hex.EncodeToString(sum[:])
+}
+
+// @brief add puts a url entry for srv/<slug>.jar into the manifest.
+func (ti *testInstall) add(t *testing.T, slug string, edit func(e *manifest.Entry)) {
+	t.Helper()
+	_, sum := body(slug + ".jar")
+	e := manifest.Entry{Slug: slug, Source: source.URL, URL: ti.srv.URL + "/" + slug + ".jar", Checksum: sum, Version: sum, Enable: true}
+	if edit != nil {
+		edit(&e)
+	}
+	if err := ti.man.AddEntry(ti.mods, e); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// @brief write puts a file into the game dir's mods folder.
+func (ti *testInstall) write(t *testing.T, name string, data []byte) {
+	t.Helper()
+	dir := filepath.Join(ti.game, "mods")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (ti *testInstall) installer(sink events.Sink) *Installer {
+	return &Installer{
+		gameDir: ti.game,
+		man:     ti.man,
+		http:    ti.srv.Client(),
+		dl:      ti.srv.Client(),
+		events:  sink,
+		concur:  4,
+	}
+}
+
+func outcomes(rep *Report) map[string]Outcome {
+	out := map[string]Outcome{}
+	for _, r := range rep.Results {
+		out[r.Slug] = r.Outcome
+	}
+	return out
+}
+
+func TestInstallReport(t *testing.T) {
+	ti := newTestInstall(t)
+	ti.add(t, "fresh", nil)
+	ti.add(t, "there", nil)
+	ti.add(t, "renamed", nil)
+	ti.add(t, "clientless", func(e *manifest.Entry) { e.ClientSide, e.ServerSide = "unsupported", "required" })
+	ti.add(t, "off", func(e *manifest.Entry) { e.Enable = false })
+	ti.add(t, "gone", nil)
+	ti.add(t, "tampered", func(e *manifest.Entry) {
+		_, other := body("never-served.jar")
+		e.Checksum, e.Version = other, other
+	})
+	b, _ := body("there.jar")
+	ti.write(t, "there.jar", b)
+	b, _ = body("renamed.jar")
+	ti.write(t, "renamed-by-hand.jar", b)
+
+	rec := &recorder{}
+	rep, err := ti.installer(rec).Install(context.Background(), false)
+	if err != nil {
+		t.Fatalf("best-effort install returned %v", err)
+	}
+	want := map[string]Outcome{
+		"fresh":      Installed,
+		"there":      Present,
+		"renamed":    Healed,
+		"clientless": Skipped,
+		"off":        Disabled,
+		"gone":       Failed,
+		"tampered":   Failed,
+	}
+	if got := outcomes(rep); !reflect.DeepEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+	var order []string
+	for _, r := range rep.Results {
+		order = append(order, r.Slug)
+	}
+	if want := []string{"fresh", "there", "renamed", "clientless", "off", "gone", "tampered"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("results in order %v, want manifest order %v", order, want)
+	}
+
+	for _, ev := range []struct {
+		slug string
+		kind events.Kind
+	}{
+		{"fresh", events.Placed},
+		{"there", events.Present},
+		{"renamed", events.Present},
+		{"clientless", events.Skipped},
+		{"gone", events.Failed},
+		{"tampered", events.Failed},
+	} {
+		if !rec.has(ev.slug, ev.kind) {
+			t.Errorf("no %s event for %s", ev.kind, ev.slug)
+		}
+	}
+	if rec.has("fresh", events.Failed) || rec.has("off", events.Resolve) {
+		t.Error("events for entries that should not have them")
+	}
+
+	// saved once at the end, with the file names found on disk
+	saved, err := manifest.Load(ti.man.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slug, name := range map[string]string{"fresh": "fresh.jar", "renamed": "renamed-by-hand.jar"} {
+		if _, e := saved.Find(slug); e == nil || e.Filename != name {
+			t.Errorf("saved %s = %+v, want filename %s", slug, e, name)
+		}
+	}
+
+	// a second run finds everything in place
+	rep, err = ti.installer(&recorder{}).Install(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := outcomes(rep); got["fresh"] != Present || got["renamed"] != Present {
+		t.Errorf("second run outcomes = %v, want fresh and renamed present", got)
+	}
+}
+
+func TestInstallFailFast(t *testing.T) {
+	ti := newTestInstall(t)
+	ti.add(t, "gone", nil)
+	rec := &recorder{}
+	if _, err := ti.installer(rec).Install(context.Background(), true); err == nil {
+		t.Fatal("fail-fast install returned no error")
+	}
+	if rec.has("gone", events.Failed) != true {
+		t.Error("no failed event for gone")
+	}
+}
+
+func TestPinSums(t *testing.T) {
+	e := manifest.Entry{Version: "4711", Filename: "jei.jar", Checksum: "aa", SHA512: "bb"}
+	tests := []struct {
+		name     string
+		rel      source.Release
+		wantSHA1 string
+	}{
+		{name: "no hashes, same file", rel: source.Release{Version: "4711", Filename: "jei.jar"}, wantSHA1: "aa"},
+		{name: "no hashes, new version", rel: source.Release{Version: "4712", Filename: "jei.jar"}},
+		{name: "no hashes, other file", rel: source.Release{Version: "4711", Filename: "jei-api.jar"}},
+		{name: "published hash wins", rel: source.Release{Version: "4711", Filename: "jei.jar", SHA1: "cc"}, wantSHA1: "cc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel := tt.rel
+			if got := pinSums(&rel, e).SHA1; got != tt.wantSHA1 {
+				t.Errorf("SHA1 = %q, want %q", got, tt.wantSHA1)
+			}
+		})
+	}
+}
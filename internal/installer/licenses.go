@@ -0,0 +1,31 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/silask7188/ModrinthCLI/internal/license"
+)
+
+// @brief LicenseViolations checks entries against the pack's license policy.
+// Project pages come from the cache (see Projects).
+// @param ctx context for cancellation
+// @param slugs entries to check
+// @return the policy (nil if the pack has none), "slug: reason" lines, or error
+func (ins *Installer) LicenseViolations(ctx context.Context, slugs []string) (*license.Policy, []string, error) {
+	pol, err := license.LoadPolicy(ins.man.Dir())
+	if err != nil || pol == nil {
+		return nil, nil, err
+	}
+	prjs, err := ins.Projects(ctx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var out []string
+	for _, slug := range slugs {
+		if reason := pol.Check(slug, prjs[slug].License); reason != "" {
+			out = append(out, fmt.Sprintf("%s: %s", slug, reason))
+		}
+	}
+	return pol, out, nil
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package installer
+
+import "os"
+
+// lockFile claims path by creating it. A lock left by a crashed install only
+// costs that URL its resume until 'mod cache gc' prunes it.
+func lockFile(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return func() { os.Remove(path) }, nil
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package installer
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockFile takes an exclusive lock on path without waiting. The lock goes
+// away with the process, so a crashed install never leaves it behind.
+func lockFile(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return nil, err
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now) // cache gc prunes lock files by age
+	return func() { f.Close() }, nil
+}
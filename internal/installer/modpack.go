@@ -0,0 +1,320 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/mrpack"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// Pack is a modpack opened for import.
+type Pack struct {
+	*mrpack.Archive
+	Ref manifest.Modpack
+	tmp string // downloaded archive, removed on Close
+}
+
+// @brief Close releases the archive and removes a downloaded copy.
+func (p *Pack) Close() error {
+	err := p.Archive.Close()
+	if p.tmp != "" {
+		os.Remove(p.tmp)
+	}
+	return err
+}
+
+// PackEntry is a manifest entry derived from one modpack file.
+type PackEntry struct {
+	Type  manifest.ContentType
+	Entry manifest.Entry
+}
+
+// PackChange is one difference between the manifest and a modpack version.
+type PackChange struct {
+	Type manifest.ContentType
+	Old  *manifest.Entry // nil = added by the pack
+	New  *manifest.Entry // nil = dropped by the pack
+}
+
+// @brief OpenPack opens a local .mrpack, or downloads the newest version of a Modrinth modpack.
+// @param ctx context for cancellation
+// @param from path to a .mrpack file, or a modpack slug / url
+// @return opened pack, to be closed by the caller, or error
+func (ins *Installer) OpenPack(ctx context.Context, from string) (*Pack, error) {
+	if fi, err := os.Stat(from); err == nil && !fi.IsDir() {
+		a, err := mrpack.Open(from)
+		if err != nil {
+			return nil, err
+		}
+		return &Pack{Archive: a, Ref: manifest.Modpack{Name: a.Index.Name, VersionNumber: a.Index.VersionID}}, nil
+	}
+
+	slug := modrinth.ParseSlug(from)
+	prj, err := ins.mr.LookupProject(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("modpack %q not found: %w", slug, err)
+	}
+	if prj.ProjectType != "modpack" {
+		return nil, fmt.Errorf("%s is a %s, not a modpack", prj.Slug, prj.ProjectType)
+	}
+
+	// a pack update may move to a newer Minecraft, so only the loader is kept
+	f := source.Filter{}
+	if ins.man.Minecraft.Loader != "" {
+		f.Loader = ins.man.Minecraft.Loader
+	}
+	vers, err := ins.mr.ListVersions(ctx, prj.ID, f)
+	if err != nil {
+		return nil, err
+	}
+	if len(vers) == 0 {
+		return nil, fmt.Errorf("no versions of %s for %s", prj.Slug, ins.man.Minecraft.Loader)
+	}
+	sort.Slice(vers, func(i, j int) bool {
+		return vers[i].Published > vers[j].Published
+	})
+	latest := vers[0]
+
+	files, err := ins.mr.ResolveFiles(ctx, prj.ID, latest.ID)
+	if err != nil {
+		return nil, err
+	}
+	var rel *source.Release
+	for i := range files {
+		if strings.HasSuffix(files[i].Filename, ".mrpack") {
+			rel = &files[i]
+			break
+		}
+	}
+	if rel == nil {
+		return nil, fmt.Errorf("%s %s has no .mrpack file", prj.Slug, latest.Number)
+	}
+
+	tmp, _, err := ins.download(ctx, prj.Slug, rel)
+	if err != nil {
+		return nil, err
+	}
+	a, err := mrpack.Open(tmp)
+	if err != nil {
+		ins.discard(tmp)
+		return nil, err
+	}
+	if ins.cache.Holds(tmp) {
+		tmp = "" // keep the cached copy
+	}
+	return &Pack{
+		Archive: a,
+		Ref: manifest.Modpack{
+			Name:          prj.Title,
+			Slug:          prj.Slug,
+			Version:       latest.ID,
+			VersionNumber: latest.Number,
+		},
+		tmp: tmp,
+	}, nil
+}
+
+// @brief PackEntries maps the files of a modpack to manifest entries.
+// Files Modrinth knows by hash become pinned Modrinth entries, the rest url entries.
+// @param ctx context for cancellation
+// @param idx modpack index
+// @return entries in index order, or error
+func (ins *Installer) PackEntries(ctx context.Context, idx *mrpack.Index) ([]PackEntry, error) {
+	hashes := make([]string, 0, len(idx.Files))
+	for _, f := range idx.Files {
+		hashes = append(hashes, f.Hashes.SHA1)
+	}
+	byHash, err := ins.mr.VersionsByHash(ctx, "sha1", hashes)
+	if err != nil {
+		return nil, fmt.Errorf("hash lookup failed: %w", err)
+	}
+
+	ids := map[string]bool{}
+	for _, v := range byHash {
+		ids[v.ProjectID] = true
+	}
+	idList := make([]string, 0, len(ids))
+	for id := range ids {
+		idList = append(idList, id)
+	}
+	prjs, err := ins.mr.GetProjects(ctx, idList)
+	if err != nil {
+		return nil, fmt.Errorf("project lookup failed: %w", err)
+	}
+	byID := map[string]modrinth.Project{}
+	for _, p := range prjs {
+		byID[p.Id] = p
+	}
+
+	var out []PackEntry
+	seen := map[string]int{} // section/slug -> index in out, to merge per-world copies
+	for _, f := range idx.Files {
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			return nil, fmt.Errorf("modpack file %q is outside the game dir", f.Path)
+		}
+		ct, dest, world := manifest.TypeForPath(f.Path)
+		e := manifest.Entry{
+			Dest:     dest,
+			Filename: path.Base(f.Path),
+			Checksum: f.Hashes.SHA1,
+			SHA512:   f.Hashes.SHA512,
+			Enable:   true,
+			Modpack:  true,
+		}
+		if f.Env != nil {
+			e.ClientSide, e.ServerSide = f.Env.Client, f.Env.Server
+		}
+
+		v, known := byHash[f.Hashes.SHA1]
+		prj, found := byID[v.ProjectID]
+		switch {
+		case known && found:
+			e.Slug = prj.Slug
+			e.ProjectID = prj.Id
+			e.Version = v.ID
+			e.VersionNumber = v.VersionNumber
+			if f.Env == nil {
+				e.ClientSide, e.ServerSide = prj.ClientSide, prj.ServerSide
+			}
+		case len(f.Downloads) > 0:
+			e.Slug = strings.TrimSuffix(e.Filename, path.Ext(e.Filename))
+			e.Source = source.URL
+			e.URL = f.Downloads[0]
+			e.Version = f.Hashes.SHA1
+			e.VersionNumber = f.Hashes.SHA1[:min(8, len(f.Hashes.SHA1))]
+		default:
+			return nil, fmt.Errorf("%s has no download url", f.Path)
+		}
+
+		key := ct.Section + "/" + e.Slug
+		if i, ok := seen[key]; ok {
+			if world != "" {
+				out[i].Entry.Worlds = append(out[i].Entry.Worlds, world)
+			}
+			continue
+		}
+		if world != "" {
+			e.Worlds = []string{world}
+		}
+		seen[key] = len(out)
+		out = append(out, PackEntry{Type: ct, Entry: e})
+	}
+	return out, nil
+}
+
+// @brief PackDiff compares the manifest's modpack entries with a modpack version.
+// Entries the user added are left out of the comparison.
+// @param entries entries of the new pack version (see PackEntries)
+// @return changes, removals last
+func (ins *Installer) PackDiff(entries []PackEntry) []PackChange {
+	cur := map[string]*manifest.Entry{}
+	for _, ct := range manifest.ContentTypes() {
+		list := ins.man.Entries(ct)
+		for i := range list {
+			if list[i].Modpack {
+				cur[ct.Section+"/"+list[i].Slug] = &list[i]
+			}
+		}
+	}
+
+	var out []PackChange
+	for i := range entries {
+		pe := &entries[i]
+		key := pe.Type.Section + "/" + pe.Entry.Slug
+		old, ok := cur[key]
+		delete(cur, key)
+		if ok && old.Version == pe.Entry.Version && old.Dest == pe.Entry.Dest {
+			continue
+		}
+		out = append(out, PackChange{Type: pe.Type, Old: old, New: &pe.Entry})
+	}
+
+	for _, ct := range manifest.ContentTypes() {
+		list := ins.man.Entries(ct)
+		for i := range list {
+			if old, gone := cur[ct.Section+"/"+list[i].Slug]; gone && old == &list[i] {
+				out = append(out, PackChange{Type: ct, Old: &list[i]})
+			}
+		}
+	}
+	return out
+}
+
+// @brief PackMinecraft reads the game and loader versions a modpack depends on.
+// @param base current values, kept where the pack says nothing
+// @param idx modpack index
+// @return updated Minecraft header
+func PackMinecraft(base manifest.Minecraft, idx *mrpack.Index) manifest.Minecraft {
+	mc := base
+	for key, ver := range idx.Dependencies {
+		if key == "minecraft" {
+			mc.Version = ver
+		} else if loader, ok := mrpack.LoaderFromKey(key); ok {
+			mc.Loader, mc.LoaderVersion = loader, ver
+		}
+	}
+	return mc
+}
+
+// @brief ApplyPack moves the manifest to a modpack version and saves it.
+// Dropped and replaced entries are removed from disk; the user's enable state
+// is kept; overrides for this side are extracted next to the manifest.
+// @param p opened modpack
+// @param changes result of PackDiff for the same pack
+// @return error if the manifest or overrides could not be written
+func (ins *Installer) ApplyPack(p *Pack, changes []PackChange) error {
+	ins.man.Minecraft = PackMinecraft(ins.man.Minecraft, &p.Index)
+
+	// detach from the manifest: removing entries shifts the slices Old points into
+	todo := make([]PackChange, len(changes))
+	for i, c := range changes {
+		todo[i] = PackChange{Type: c.Type, Old: cloneEntry(c.Old), New: cloneEntry(c.New)}
+	}
+
+	for _, c := range todo {
+		if c.Old != nil {
+			if err := ins.man.Remove(ins.gameDir, c.Old.Slug); err != nil {
+				return err
+			}
+		}
+		if c.New != nil {
+			if c.Old != nil {
+				c.New.Enable = c.Old.Enable
+			}
+			if err := ins.man.AddEntry(c.Type, *c.New); err != nil {
+				return err
+			}
+		}
+	}
+
+	folders := []string{overrides.Common, overrides.Client}
+	if ins.man.TargetSide() == manifest.SideServer {
+		folders[1] = overrides.Server
+	}
+	for _, folder := range folders {
+		if _, err := p.Extract(folder, filepath.Join(ins.man.Dir(), folder)); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", folder, err)
+		}
+	}
+
+	ref := p.Ref
+	ins.man.Modpack = &ref
+	return ins.man.Save()
+}
+
+func cloneEntry(e *manifest.Entry) *manifest.Entry {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	return &c
+}
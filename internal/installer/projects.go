@@ -0,0 +1,142 @@
+package installer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+const (
+	projectCacheFile = "projects.json"
+	projectCacheTTL  = 24 * time.Hour
+)
+
+// projectCache keeps project pages between runs, keyed by "source:id".
+type projectCache struct {
+	Projects map[string]cachedProject `json:"projects"`
+}
+
+type cachedProject struct {
+	Fetched time.Time      `json:"fetched"`
+	Project source.Project `json:"project"`
+}
+
+// @brief Projects returns the project page of every manifest entry.
+// Pages come from .modcli/projects.json when younger than a day; entries
+// without a provider (url, file, github, maven) get one built from the entry.
+// @param ctx context for cancellation
+// @param refresh ignore the cache and fetch everything again
+// @return projects keyed by entry slug, or error if a lookup failed
+func (ins *Installer) Projects(ctx context.Context, refresh bool) (map[string]source.Project, error) {
+	cache := ins.loadProjectCache()
+	now := time.Now()
+	out := map[string]source.Project{}
+
+	var want []PackEntry // not cached or stale
+	for _, ct := range manifest.ContentTypes() {
+		for _, e := range ins.man.Entries(ct) {
+			switch e.SourceName() {
+			case source.Modrinth, source.CurseForge:
+			default:
+				out[e.Slug] = localProject(ct, e)
+				continue
+			}
+			c, ok := cache.Projects[cacheKey(e)]
+			if ok && !refresh && now.Sub(c.Fetched) < projectCacheTTL {
+				out[e.Slug] = c.Project
+				continue
+			}
+			want = append(want, PackEntry{Type: ct, Entry: e})
+		}
+	}
+	if len(want) == 0 {
+		return out, nil
+	}
+
+	// Modrinth in one batch, CurseForge one by one
+	var ids []string
+	for _, w := range want {
+		if w.Entry.SourceName() == source.Modrinth {
+			ids = append(ids, projectID(w.Entry))
+		}
+	}
+	prjs, err := ins.mr.LookupProjects(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("project lookup failed: %w", err)
+	}
+	fetched := map[string]source.Project{}
+	for _, p := range prjs {
+		fetched[p.ID], fetched[p.Slug] = p, p
+	}
+	for _, w := range want {
+		e := w.Entry
+		var p source.Project
+		switch e.SourceName() {
+		case source.Modrinth:
+			var ok bool
+			if p, ok = fetched[projectID(e)]; !ok {
+				out[e.Slug] = localProject(w.Type, e) // deleted or hidden project
+				continue
+			}
+		case source.CurseForge:
+			cf, err := ins.provider(e)
+			if err != nil {
+				out[e.Slug] = localProject(w.Type, e) // no API key: list it without details
+				continue
+			}
+			prj, err := cf.LookupProject(ctx, projectID(e))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", e.Slug, err)
+			}
+			p = *prj
+		}
+		out[e.Slug] = p
+		cache.Projects[cacheKey(e)] = cachedProject{Fetched: now, Project: p}
+	}
+	return out, ins.saveProjectCache(cache)
+}
+
+func cacheKey(e manifest.Entry) string {
+	return e.SourceName() + ":" + projectID(e)
+}
+
+// @brief localProject describes an entry that has no project page.
+func localProject(ct manifest.ContentType, e manifest.Entry) source.Project {
+	p := source.Project{ID: e.Slug, Slug: e.Slug, Title: e.Slug, ProjectType: ct.Name}
+	switch e.SourceName() {
+	case source.URL:
+		p.URL = e.URL
+	case source.GitHub:
+		p.URL = "https://github.com/" + e.Repo
+		p.Author, _, _ = strings.Cut(e.Repo, "/")
+	case source.Maven:
+		p.URL = e.Repository
+	}
+	return p
+}
+
+func (ins *Installer) loadProjectCache() *projectCache {
+	c := &projectCache{Projects: map[string]cachedProject{}}
+	b, err := os.ReadFile(filepath.Join(ins.gameDir, overrides.StateDir, projectCacheFile))
+	if err == nil && json.Unmarshal(b, c) == nil && c.Projects != nil {
+		return c
+	}
+	return &projectCache{Projects: map[string]cachedProject{}} // missing or corrupt: start over
+}
+
+func (ins *Installer) saveProjectCache(c *projectCache) error {
+	dir := filepath.Join(ins.gameDir, overrides.StateDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, _ := json.MarshalIndent(c, "", " ")
+	return os.WriteFile(filepath.Join(dir, projectCacheFile), b, 0o644)
+}
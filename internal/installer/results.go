@@ -0,0 +1,48 @@
+package installer
+
+import "sort"
+
+// Outcome is what an install did with one entry.
+type Outcome string
+
+const (
+	Installed Outcome = "installed" // downloaded and placed
+	Present   Outcome = "present"   // the right file was already there
+	Healed    Outcome = "healed"    // found under another name, manifest fixed
+	Skipped   Outcome = "skipped"   // not for this side, or nowhere to put it
+	Disabled  Outcome = "disabled"  // disabled in the manifest
+	Failed    Outcome = "failed"
+)
+
+// Result is the outcome of one entry.
+type Result struct {
+	Slug    string
+	Type    string // content type name
+	Outcome Outcome
+	Version string // version number, when known
+	Reason  string // why it was skipped or failed
+	order   int    // position in the manifest
+}
+
+// Report collects the results of an install.
+type Report struct {
+	Results []Result
+}
+
+// @brief Count returns how many entries had an outcome.
+func (r *Report) Count(o Outcome) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+// sort puts results back in manifest order, workers finish in any order.
+func (r *Report) sort() {
+	sort.SliceStable(r.Results, func(i, j int) bool {
+		return r.Results[i].order < r.Results[j].order
+	})
+}
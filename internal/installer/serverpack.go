@@ -0,0 +1,161 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/silask7188/ModrinthCLI/internal/events"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/serverpack"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// serverWorld is the world folder a fresh server creates (level-name=world).
+const serverWorld = "world"
+
+// ServerPack is a server pack being assembled; Close removes its downloads.
+type ServerPack struct {
+	serverpack.Pack
+	Included []string // entries in the pack
+	Skipped  []string // enabled entries left out as client-only
+	tmp      []string
+}
+
+// @brief Close removes the temp files downloaded for the pack.
+func (p *ServerPack) Close() error {
+	for _, f := range p.tmp {
+		os.Remove(f)
+	}
+	return nil
+}
+
+// @brief ServerPack collects what a dedicated server needs: enabled mods,
+// datapacks and plugins that run on the server, overrides/ layered with
+// server-overrides/, and the loader's server launcher. Installed files are
+// reused when they still match the manifest; anything else is downloaded at
+// the version the manifest records.
+// @param ctx context for cancellation
+// @param loaderVersion loader version for the launcher, empty = the manifest's
+// @param launcher download the launcher into the pack instead of leaving it to the start scripts
+// @return pack ready to write (call Close afterwards), or error
+func (ins *Installer) ServerPack(ctx context.Context, loaderVersion string, launcher bool) (*ServerPack, error) {
+	if err := ins.fillSides(ctx); err != nil {
+		return nil, err
+	}
+	mc := ins.man.Minecraft
+	if loaderVersion != "" {
+		mc.LoaderVersion = loaderVersion
+	}
+	l, err := serverpack.ResolveLauncher(ctx, ins.http, mc)
+	if err != nil {
+		return nil, err
+	}
+	sp := &ServerPack{Pack: serverpack.Pack{Files: map[string]string{}, Launcher: l}}
+
+	for _, ct := range manifest.ContentTypes() {
+		if ct.ClientOnly {
+			continue
+		}
+		for _, e := range ins.man.Entries(ct) {
+			if !e.Enable {
+				continue
+			}
+			if !e.SupportsSide(manifest.SideServer) {
+				sp.Skipped = append(sp.Skipped, e.Slug)
+				continue
+			}
+			file, name, err := ins.serverFile(ctx, sp, ct, e)
+			if err != nil {
+				sp.Close()
+				return nil, fmt.Errorf("%s: %w", e.Slug, err)
+			}
+			dest := e.Dest
+			if ct.PerWorld {
+				dest = path.Join(serverWorld, e.Dest)
+			}
+			sp.Files[path.Join(filepath.ToSlash(dest), name)] = file
+			sp.Included = append(sp.Included, e.Slug)
+		}
+	}
+
+	// later folders win
+	for _, dir := range overrides.Dirs(ins.man.Dir(), manifest.SideServer) {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			sp.Files[filepath.ToSlash(rel)] = p
+			return nil
+		})
+		if err != nil {
+			sp.Close()
+			return nil, err
+		}
+	}
+
+	if launcher && l.URL != "" {
+		tmp, _, err := ins.download(ctx, l.File, &source.Release{URL: l.URL, Filename: l.File})
+		if err != nil {
+			sp.Close()
+			return nil, fmt.Errorf("failed to download %s: %w", l.File, err)
+		}
+		ins.track(sp, tmp)
+		sp.LauncherFile = tmp
+	}
+	return sp, nil
+}
+
+// @brief track has Close remove a download, unless the cache holds it.
+func (ins *Installer) track(sp *ServerPack, path string) {
+	if !ins.cache.Holds(path) {
+		sp.tmp = append(sp.tmp, path)
+	}
+}
+
+// @brief serverFile finds or downloads the file of one server entry.
+// @param ctx context for cancellation
+// @param sp pack collecting temp files
+// @param ct content type of the entry
+// @param e manifest entry
+// @return path on disk, file name in the pack, or error
+func (ins *Installer) serverFile(ctx context.Context, sp *ServerPack, ct manifest.ContentType, e manifest.Entry) (string, string, error) {
+	if local, err := ins.localCopy(ct, e); err == nil {
+		if ok, _ := ins.man.Hashes().Matches(local, e.Sums()); ok {
+			return local, e.Filename, nil
+		}
+	}
+
+	// not installed on this side (client-skipped server mods) or changed
+	var rel *source.Release
+	var err error
+	if e.Version != "" {
+		rel, err = ins.exportRelease(ctx, ct, e)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if rel == nil {
+		if rel, err = ins.resolve(ctx, ct, e); err != nil {
+			return "", "", err
+		}
+	}
+	if rel.Path != "" {
+		return rel.Path, rel.Filename, nil
+	}
+	tmp, _, err := ins.download(ctx, e.Slug, rel)
+	if err != nil {
+		return "", "", err
+	}
+	ins.track(sp, tmp)
+	ins.events.Event(events.Event{Kind: events.Placed, Slug: e.Slug, Version: rel.VersionNumber})
+	return tmp, rel.Filename, nil
+}
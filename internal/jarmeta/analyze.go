@@ -0,0 +1,215 @@
+package jarmeta
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Jar is a scanned file from the mods folder.
+type Jar struct {
+	File  string // file name
+	Owner string // manifest slug, empty if untracked
+	Info  *Info
+}
+
+// Env is what the instance itself provides to jars.
+type Env struct {
+	Minecraft     string // game version
+	Loader        string // manifest loader ("fabric", "forge", ...)
+	LoaderVersion string // empty or "latest" when unknown
+}
+
+// Issue is one problem found in the mods folder.
+type Issue struct {
+	Kind    string // "duplicate", "depends", "breaks", "loader" or "unreadable"
+	Message string
+}
+
+// @brief Label names a jar with the manifest entry it belongs to.
+// @return "sodium-0.5.3.jar (sodium)" or "old.jar (untracked)"
+func (j Jar) Label() string {
+	owner := j.Owner
+	if owner == "" {
+		owner = "untracked"
+	}
+	return fmt.Sprintf("%s (%s)", j.File, owner)
+}
+
+// @brief Scan reads the metadata of every enabled jar in a folder.
+// Files without mod metadata are skipped, unreadable ones reported.
+// @param dir mods folder
+// @param owners manifest slug by file name
+// @return jars with metadata and read problems, or error if dir is unreadable
+func Scan(dir string, owners map[string]string) ([]Jar, []Issue, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	var jars []Jar
+	var issues []Issue
+	for _, f := range files {
+		if f.IsDir() || !strings.EqualFold(filepath.Ext(f.Name()), ".jar") {
+			continue
+		}
+		j := Jar{File: f.Name(), Owner: owners[f.Name()]}
+		info, err := Read(filepath.Join(dir, f.Name()))
+		switch {
+		case errors.Is(err, ErrNoMetadata):
+			continue
+		case err != nil:
+			issues = append(issues, Issue{Kind: "unreadable", Message: fmt.Sprintf("%s: %v", j.Label(), err)})
+			continue
+		case info.Format == "pack":
+			continue
+		}
+		j.Info = info
+		jars = append(jars, j)
+	}
+	return jars, issues, nil
+}
+
+// provider is a mod id some jar (or a jar inside it) answers to.
+type provider struct {
+	jar     Jar
+	version string
+}
+
+// @brief Analyze looks for duplicate mod ids, unmet or broken dependencies and
+// jars built for another loader.
+// @param jars result of Scan
+// @param env versions the instance provides
+// @return issues, in a stable order
+func Analyze(jars []Jar, env Env) []Issue {
+	var issues []Issue
+
+	// top-level ids must be unique; bundled copies are deduplicated by the loader
+	top := map[string][]Jar{}
+	provided := map[string][]provider{}
+	for _, j := range jars {
+		for _, id := range append([]string{j.Info.ID}, j.Info.Provides...) {
+			top[id] = append(top[id], j)
+			provided[id] = append(provided[id], provider{j, j.Info.Version})
+		}
+		for _, n := range allNested(j.Info) {
+			for _, id := range append([]string{n.ID}, n.Provides...) {
+				provided[id] = append(provided[id], provider{j, n.Version})
+			}
+		}
+	}
+	ids := make([]string, 0, len(top))
+	for id, js := range top {
+		if len(js) > 1 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		labels := make([]string, 0, len(top[id]))
+		for _, j := range top[id] {
+			labels = append(labels, j.Label())
+		}
+		issues = append(issues, Issue{Kind: "duplicate", Message: fmt.Sprintf("%q is provided by %s", id, strings.Join(labels, ", "))})
+	}
+
+	builtin := builtins(env)
+	for _, j := range jars {
+		info := j.Info
+		if !loaderAccepts(env.Loader, info.Format) {
+			issues = append(issues, Issue{Kind: "loader", Message: fmt.Sprintf("%s: is a %s mod, the manifest uses %s", j.Label(), info.Format, env.Loader)})
+			continue // its dependencies are moot
+		}
+		for _, d := range info.Depends {
+			if ver, ok := builtin[d.ID]; ok {
+				if ver != "" && !Satisfies(info.Format, ver, d.Range) {
+					kind := "depends"
+					if d.ID != "minecraft" {
+						kind = "loader"
+					}
+					issues = append(issues, Issue{Kind: kind, Message: fmt.Sprintf("%s: needs %s %s, manifest has %s", j.Label(), d.ID, d.Range, ver)})
+				}
+				continue
+			}
+			ps := provided[d.ID]
+			if len(ps) == 0 {
+				issues = append(issues, Issue{Kind: "depends", Message: fmt.Sprintf("%s: needs %s, which is not installed", j.Label(), d.String())})
+				continue
+			}
+			ok := false
+			var have []string
+			for _, p := range ps {
+				if Satisfies(info.Format, p.version, d.Range) {
+					ok = true
+					break
+				}
+				have = append(have, fmt.Sprintf("%s in %s", p.version, p.jar.File))
+			}
+			if !ok {
+				issues = append(issues, Issue{Kind: "depends", Message: fmt.Sprintf("%s: needs %s, found %s", j.Label(), d.String(), strings.Join(have, ", "))})
+			}
+		}
+		for _, d := range info.Breaks {
+			for _, p := range provided[d.ID] {
+				if p.jar.File != j.File && Satisfies(info.Format, p.version, d.Range) {
+					issues = append(issues, Issue{Kind: "breaks", Message: fmt.Sprintf("%s: breaks with %s, found %s in %s", j.Label(), d.String(), p.version, p.jar.Label())})
+					break
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// builtins maps ids the game and loader provide to their versions; an empty
+// version means present but unknown.
+func builtins(env Env) map[string]string {
+	lv := env.LoaderVersion
+	if lv == "latest" {
+		lv = ""
+	}
+	b := map[string]string{"minecraft": env.Minecraft, "java": ""}
+	switch env.Loader {
+	case "fabric":
+		b["fabricloader"] = lv
+	case "quilt":
+		b["quilt_loader"] = lv
+		b["fabricloader"] = "" // quilt answers with its own compatibility version
+	case "forge":
+		b["forge"] = lv
+	case "neoforge":
+		b["neoforge"] = lv
+		b["forge"] = ""
+	}
+	return b
+}
+
+// loaderAccepts reports whether a loader runs jars of a metadata format.
+// Loaders we know nothing about accept everything.
+func loaderAccepts(loader, format string) bool {
+	switch loader {
+	case "fabric":
+		return format == "fabric"
+	case "quilt":
+		return format == "quilt" || format == "fabric"
+	case "forge":
+		return format == "forge"
+	case "neoforge":
+		return format == "neoforge" || format == "forge"
+	}
+	return true
+}
+
+func allNested(info *Info) []*Info {
+	var out []*Info
+	for _, n := range info.Nested {
+		out = append(out, n)
+		out = append(out, allNested(n)...)
+	}
+	return out
+}
@@ -0,0 +1,463 @@
+package jarmeta
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// metadata files, relative to the jar or pack root
+const (
+	FabricFile   = "fabric.mod.json"
+	QuiltFile    = "quilt.mod.json"
+	ForgeFile    = "META-INF/mods.toml"
+	NeoForgeFile = "META-INF/neoforge.mods.toml"
+	PackFile     = "pack.mcmeta"
+	manifestFile = "META-INF/MANIFEST.MF"
+)
+
+// ErrNoMetadata is returned for archives without any known metadata file.
+var ErrNoMetadata = errors.New("no mod or pack metadata")
+
+// Info is what a jar or pack says about itself.
+type Info struct {
+	Format      string // "fabric", "quilt", "forge", "neoforge" or "pack"
+	ID          string
+	Name        string
+	Version     string
+	Description string
+	License     string
+	Environment string // "client", "server", "*" for both, empty if not declared
+	Minecraft   string // accepted Minecraft versions, as declared
+	Loader      string // accepted loader versions, as declared
+	Depends     []Dep
+	Breaks      []Dep
+	Provides    []string // further ids this jar answers to
+	PackFormat  int      // pack.mcmeta only
+	Nested      []*Info  // jars bundled inside (jar-in-jar)
+}
+
+// Dep is a required or incompatible mod id with its version range.
+type Dep struct {
+	ID    string
+	Range string // in the loader's own syntax; "*" or empty for any
+}
+
+// @brief String formats a dependency as "id range", or just "id" for any version.
+func (d Dep) String() string {
+	if d.Range == "" || d.Range == "*" {
+		return d.ID
+	}
+	return d.ID + " " + d.Range
+}
+
+// @brief Read reads the metadata of a jar, zip or unpacked pack folder.
+// @param path file or folder
+// @return metadata, ErrNoMetadata, or another error
+func Read(path string) (*Info, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return readFS(os.DirFS(path))
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readFS(zr)
+}
+
+// @brief ReadZip reads the metadata of an opened archive.
+// @param zr archive
+// @return metadata, ErrNoMetadata, or another error
+func ReadZip(zr *zip.Reader) (*Info, error) {
+	return readFS(zr)
+}
+
+// readFS tries every format, mod metadata before pack.mcmeta: mods often ship one too.
+func readFS(fsys fs.FS) (*Info, error) {
+	readers := []struct {
+		file string
+		read func(fs.FS, []byte) (*Info, error)
+	}{
+		{QuiltFile, readQuilt}, // quilt jars may carry a fabric.mod.json for compatibility
+		{FabricFile, readFabric},
+		{NeoForgeFile, readForge("neoforge")},
+		{ForgeFile, readForge("forge")},
+		{PackFile, readPack},
+	}
+	for _, r := range readers {
+		b, err := fs.ReadFile(fsys, r.file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		info, err := r.read(fsys, b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.file, err)
+		}
+		if info.Format != "pack" {
+			info.Nested = readNested(fsys)
+		}
+		return info, nil
+	}
+	return nil, ErrNoMetadata
+}
+
+// readNested reads bundled jars: META-INF/jars/ (Fabric, Quilt) and
+// META-INF/jarjar/ (Forge, NeoForge). Unreadable ones are skipped.
+func readNested(fsys fs.FS) []*Info {
+	var out []*Info
+	for _, pattern := range []string{"META-INF/jars/*.jar", "META-INF/jarjar/*.jar"} {
+		names, _ := fs.Glob(fsys, pattern)
+		for _, name := range names {
+			b, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				continue
+			}
+			zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+			if err != nil {
+				continue
+			}
+			if info, err := readFS(zr); err == nil {
+				out = append(out, info)
+			}
+		}
+	}
+	return out
+}
+
+func readFabric(_ fs.FS, b []byte) (*Info, error) {
+	var f struct {
+		ID          string                     `json:"id"`
+		Version     string                     `json:"version"`
+		Name        string                     `json:"name"`
+		Description string                     `json:"description"`
+		License     json.RawMessage            `json:"license"`
+		Environment string                     `json:"environment"`
+		Depends     map[string]json.RawMessage `json:"depends"`
+		Breaks      map[string]json.RawMessage `json:"breaks"`
+		Provides    []string                   `json:"provides"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	info := &Info{
+		Format:      "fabric",
+		ID:          f.ID,
+		Name:        f.Name,
+		Version:     f.Version,
+		Description: f.Description,
+		License:     strings.Join(stringOrList(f.License), ", "),
+		Environment: f.Environment,
+		Depends:     fabricDeps(f.Depends),
+		Breaks:      fabricDeps(f.Breaks),
+		Provides:    f.Provides,
+	}
+	if info.Environment == "" {
+		info.Environment = "*"
+	}
+	info.Minecraft = rangeOf(info.Depends, "minecraft")
+	info.Loader = rangeOf(info.Depends, "fabricloader")
+	return info, nil
+}
+
+// fabricDeps turns {"id": ">=1" | [">=1", "<2"]} into deps; a list means any of them.
+func fabricDeps(m map[string]json.RawMessage) []Dep {
+	deps := make([]Dep, 0, len(m))
+	for id, raw := range m {
+		deps = append(deps, Dep{ID: id, Range: strings.Join(stringOrList(raw), " || ")})
+	}
+	sortDeps(deps)
+	return deps
+}
+
+func readQuilt(_ fs.FS, b []byte) (*Info, error) {
+	var q struct {
+		Loader struct {
+			ID       string            `json:"id"`
+			Version  string            `json:"version"`
+			Depends  []json.RawMessage `json:"depends"`
+			Breaks   []json.RawMessage `json:"breaks"`
+			Provides []json.RawMessage `json:"provides"`
+			Metadata struct {
+				Name        string          `json:"name"`
+				Description string          `json:"description"`
+				License     json.RawMessage `json:"license"`
+			} `json:"metadata"`
+		} `json:"quilt_loader"`
+		Minecraft struct {
+			Environment string `json:"environment"`
+		} `json:"minecraft"`
+	}
+	if err := json.Unmarshal(b, &q); err != nil {
+		return nil, err
+	}
+	l := q.Loader
+	info := &Info{
+		Format:      "quilt",
+		ID:          l.ID,
+		Name:        l.Metadata.Name,
+		Version:     l.Version,
+		Description: l.Metadata.Description,
+		License:     quiltLicense(l.Metadata.License),
+		Environment: q.Minecraft.Environment,
+		Depends:     quiltDeps(l.Depends),
+		Breaks:      quiltDeps(l.Breaks),
+	}
+	switch info.Environment {
+	case "", "*":
+		info.Environment = "*"
+	case "dedicated_server":
+		info.Environment = "server"
+	}
+	for _, p := range quiltDeps(l.Provides) {
+		info.Provides = append(info.Provides, p.ID)
+	}
+	info.Minecraft = rangeOf(info.Depends, "minecraft")
+	info.Loader = rangeOf(info.Depends, "quilt_loader")
+	return info, nil
+}
+
+// quiltDeps reads "id" or {"id", "versions", "optional"} items, dropping optional ones.
+func quiltDeps(raws []json.RawMessage) []Dep {
+	var deps []Dep
+	for _, raw := range raws {
+		var id string
+		if json.Unmarshal(raw, &id) == nil {
+			deps = append(deps, Dep{ID: quiltID(id), Range: "*"})
+			continue
+		}
+		var d struct {
+			ID       string          `json:"id"`
+			Versions json.RawMessage `json:"versions"`
+			Optional bool            `json:"optional"`
+		}
+		if json.Unmarshal(raw, &d) != nil || d.ID == "" || d.Optional {
+			continue
+		}
+		rng := "*"
+		if len(d.Versions) > 0 {
+			if vs := stringOrList(d.Versions); vs != nil {
+				rng = strings.Join(vs, " || ")
+			} else {
+				rng = string(d.Versions) // {"all": [...]} and friends, shown as written
+			}
+		}
+		deps = append(deps, Dep{ID: quiltID(d.ID), Range: rng})
+	}
+	return deps
+}
+
+// quiltID drops the maven group quilt ids may carry: "org.quiltmc:quilt_loader".
+func quiltID(id string) string {
+	if _, name, ok := strings.Cut(id, ":"); ok {
+		return name
+	}
+	return id
+}
+
+func quiltLicense(raw json.RawMessage) string {
+	var out []string
+	var items []json.RawMessage
+	if json.Unmarshal(raw, &items) != nil {
+		items = []json.RawMessage{raw}
+	}
+	for _, it := range items {
+		var s string
+		var obj struct {
+			ID string `json:"id"`
+		}
+		switch {
+		case json.Unmarshal(it, &s) == nil:
+			out = append(out, s)
+		case json.Unmarshal(it, &obj) == nil && obj.ID != "":
+			out = append(out, obj.ID)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// modsToml is META-INF/mods.toml and META-INF/neoforge.mods.toml.
+type modsToml struct {
+	ModLoader     string `toml:"modLoader"`
+	LoaderVersion string `toml:"loaderVersion"`
+	License       string `toml:"license"`
+	Mods          []struct {
+		ModID       string `toml:"modId"`
+		Version     string `toml:"version"`
+		DisplayName string `toml:"displayName"`
+		Description string `toml:"description"`
+	} `toml:"mods"`
+	Dependencies map[string][]struct {
+		ModID        string `toml:"modId"`
+		Mandatory    *bool  `toml:"mandatory"` // forge
+		Type         string `toml:"type"`      // neoforge: required, optional, incompatible, discouraged
+		VersionRange string `toml:"versionRange"`
+		Side         string `toml:"side"`
+	} `toml:"dependencies"`
+}
+
+func readForge(format string) func(fs.FS, []byte) (*Info, error) {
+	return func(fsys fs.FS, b []byte) (*Info, error) {
+		var t modsToml
+		if _, err := toml.Decode(string(b), &t); err != nil {
+			return nil, err
+		}
+		if len(t.Mods) == 0 {
+			return nil, errors.New("no [[mods]] entry")
+		}
+		m := t.Mods[0]
+		info := &Info{
+			Format:      format,
+			ID:          m.ModID,
+			Name:        m.DisplayName,
+			Version:     m.Version,
+			Description: strings.TrimSpace(m.Description),
+			License:     t.License,
+		}
+		if strings.Contains(info.Version, "${file.jarVersion}") {
+			info.Version = strings.ReplaceAll(info.Version, "${file.jarVersion}", jarVersion(fsys))
+		}
+
+		own := map[string]bool{}
+		for _, o := range t.Mods {
+			own[o.ModID] = true
+			if o.ModID != m.ModID {
+				info.Provides = append(info.Provides, o.ModID)
+			}
+		}
+		for _, o := range t.Mods {
+			for _, d := range t.Dependencies[o.ModID] {
+				if own[d.ModID] {
+					continue
+				}
+				dep := Dep{ID: d.ModID, Range: d.VersionRange}
+				switch {
+				case d.Type == "incompatible":
+					info.Breaks = append(info.Breaks, dep)
+				case d.Type == "required", d.Type == "" && (d.Mandatory == nil || *d.Mandatory):
+					info.Depends = append(info.Depends, dep)
+				}
+			}
+		}
+		sortDeps(info.Depends)
+		sortDeps(info.Breaks)
+		info.Minecraft = rangeOf(info.Depends, "minecraft")
+		info.Loader = rangeOf(info.Depends, format)
+		return info, nil
+	}
+}
+
+// jarVersion reads Implementation-Version from the jar manifest, which
+// Forge substitutes for ${file.jarVersion}.
+func jarVersion(fsys fs.FS) string {
+	f, err := fsys.Open(manifestFile)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if v, ok := strings.CutPrefix(sc.Text(), "Implementation-Version:"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func readPack(_ fs.FS, b []byte) (*Info, error) {
+	var p struct {
+		Pack struct {
+			PackFormat  int             `json:"pack_format"`
+			Description json.RawMessage `json:"description"`
+		} `json:"pack"`
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	return &Info{
+		Format:      "pack",
+		PackFormat:  p.Pack.PackFormat,
+		Description: textOf(p.Pack.Description),
+	}, nil
+}
+
+// textOf flattens a Minecraft text component: a string, {"text": ...} or a list of those.
+func textOf(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		var sb strings.Builder
+		for _, it := range list {
+			sb.WriteString(textOf(it))
+		}
+		return sb.String()
+	}
+	var obj struct {
+		Text  string            `json:"text"`
+		Extra []json.RawMessage `json:"extra"`
+	}
+	if json.Unmarshal(raw, &obj) == nil {
+		s = obj.Text
+		for _, it := range obj.Extra {
+			s += textOf(it)
+		}
+	}
+	return s
+}
+
+// stringOrList decodes a JSON string or list of strings; nil if it is neither.
+func stringOrList(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []string{s}
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	return nil
+}
+
+func rangeOf(deps []Dep, id string) string {
+	for _, d := range deps {
+		if d.ID == id {
+			return d.Range
+		}
+	}
+	return ""
+}
+
+func sortDeps(deps []Dep) {
+	sort.Slice(deps, func(i, j int) bool { return deps[i].ID < deps[j].ID })
+}
+
+// @brief IsArchive reports whether a file name looks like something Read can open.
+// @param name file name, with or without a .disabled suffix
+// @return true for .jar and .zip files
+func IsArchive(name string) bool {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(name, ".disabled")))
+	return ext == ".jar" || ext == ".zip"
+}
@@ -0,0 +1,194 @@
+package jarmeta
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// @brief Compare orders two version strings. Dot-separated parts compare
+// numerically when both are numbers, a pre-release ("1.0-beta") sorts before
+// its release, and build metadata ("+mc1.20") is ignored.
+// @param a first version
+// @param b second version
+// @return -1, 0 or 1
+func Compare(a, b string) int {
+	a, _, _ = strings.Cut(a, "+")
+	b, _, _ = strings.Cut(b, "+")
+	ac, apre, _ := strings.Cut(a, "-")
+	bc, bpre, _ := strings.Cut(b, "-")
+	if c := compareParts(ac, bc); c != 0 {
+		return c
+	}
+	switch {
+	case apre == bpre:
+		return 0
+	case apre == "":
+		return 1
+	case bpre == "":
+		return -1
+	}
+	return compareParts(apre, bpre)
+}
+
+func compareParts(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				return cmpInt(xn, yn)
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func cmpInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// @brief Satisfies reports whether a version is inside a declared range.
+// Forge and NeoForge use Maven ranges ("[47,)"); Fabric and Quilt use
+// predicates (">=0.16 <1", "~1.20", "1.20.x"), with "||" between alternatives.
+// Ranges we cannot parse are treated as satisfied.
+// @param format Info.Format of the jar that declared the range
+// @param version version to test
+// @param rng declared range
+// @return true if the version is accepted
+func Satisfies(format, version, rng string) bool {
+	rng = strings.TrimSpace(rng)
+	if rng == "" || rng == "*" {
+		return true
+	}
+	if format == "forge" || format == "neoforge" {
+		return mavenSatisfies(version, rng)
+	}
+	for _, alt := range strings.Split(rng, "||") {
+		ok := true
+		for _, pred := range strings.Fields(alt) {
+			if !predicate(version, pred) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// predicate tests one Fabric-style comparison.
+func predicate(v, p string) bool {
+	if p == "*" {
+		return true
+	}
+	if strings.HasPrefix(p, "{") || strings.HasPrefix(p, "[") {
+		return true // quilt object form, shown as written but not evaluated
+	}
+	for _, op := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		rest, ok := strings.CutPrefix(p, op)
+		if !ok {
+			continue
+		}
+		rest = strings.TrimSuffix(strings.TrimSuffix(rest, ".x"), ".*")
+		c := Compare(v, rest)
+		switch op {
+		case ">=":
+			return c >= 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		case "<":
+			return c < 0
+		case "=":
+			return c == 0
+		case "~":
+			return c >= 0 && Compare(v, bump(rest, 1)) < 0
+		case "^":
+			return c >= 0 && Compare(v, bump(rest, 0)) < 0
+		}
+	}
+	// "1.20.x": every part before the wildcard must match
+	if parts := strings.Split(p, "."); parts[len(parts)-1] == "x" || parts[len(parts)-1] == "*" {
+		vs := strings.Split(v, ".")
+		for i, part := range parts[:len(parts)-1] {
+			if i >= len(vs) || vs[i] != part {
+				return false
+			}
+		}
+		return true
+	}
+	return Compare(v, p) == 0
+}
+
+// bump returns the smallest version above every version sharing its first
+// idx+1 parts: bump("1.20.1", 1) = "1.21".
+func bump(v string, idx int) string {
+	v, _, _ = strings.Cut(v, "-")
+	parts := strings.Split(v, ".")
+	for len(parts) <= idx {
+		parts = append(parts, "0")
+	}
+	n, err := strconv.Atoi(parts[idx])
+	if err != nil {
+		return v
+	}
+	parts[idx] = strconv.Itoa(n + 1)
+	return strings.Join(parts[:idx+1], ".")
+}
+
+var mavenRestriction = regexp.MustCompile(`[\[(][^\])]*[\])]`)
+
+// mavenSatisfies tests a Maven range: "[1.0,2.0)", "[1.20.1]", "(,1.0],[1.2,)".
+// A bare version is only a recommendation and accepts anything.
+func mavenSatisfies(v, rng string) bool {
+	rs := mavenRestriction.FindAllString(rng, -1)
+	if len(rs) == 0 {
+		return true
+	}
+	for _, r := range rs {
+		lowIncl, highIncl := r[0] == '[', r[len(r)-1] == ']'
+		body := strings.TrimSpace(r[1 : len(r)-1])
+		low, high, isRange := strings.Cut(body, ",")
+		if !isRange {
+			if Compare(v, body) == 0 {
+				return true
+			}
+			continue
+		}
+		low, high = strings.TrimSpace(low), strings.TrimSpace(high)
+		ok := true
+		if low != "" {
+			c := Compare(v, low)
+			ok = c > 0 || (lowIncl && c == 0)
+		}
+		if ok && high != "" {
+			c := Compare(v, high)
+			ok = c < 0 || (highIncl && c == 0)
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
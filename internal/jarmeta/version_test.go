@@ -0,0 +1,84 @@
+package jarmeta
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.2", "1.10", -1},
+		{"1.10", "1.2", 1},
+		{"1.0-beta", "1.0", -1},
+		{"1.0", "1.0-beta", 1},
+		{"1.0-alpha", "1.0-beta", -1},
+		{"1.0-beta.2", "1.0-beta.10", -1},
+		{"0.5.1+mc1.20", "0.5.1+mc1.19", 0},
+		{"1.20.1", "1.20", 1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		format, version, rng string
+		want                 bool
+	}{
+		{"fabric", "0.16.0", "", true},
+		{"fabric", "0.16.0", "*", true},
+		{"fabric", "0.16.0", ">=0.15", true},
+		{"fabric", "0.14.9", ">=0.15", false},
+		{"fabric", "0.16.0", ">=0.15 <1", true},
+		{"fabric", "1.0.0", ">=0.15 <1", false},
+		{"fabric", "1.20.4", "~1.20", true},
+		{"fabric", "1.21", "~1.20", false},
+		{"fabric", "1.20.1", "~1.20.1", true},
+		{"fabric", "1.21", "~1.20.1", false},
+		{"fabric", "1.99", "^1.2", true},
+		{"fabric", "2.0", "^1.2", false},
+		{"fabric", "1.20.6", "1.20.x", true},
+		{"fabric", "1.21", "1.20.x", false},
+		{"fabric", "1.19.2", "1.19.2", true},
+		{"fabric", "1.19.2", "1.20 || 1.19.2", true},
+		{"fabric", "1.18", "1.20 || 1.19.2", false},
+		{"quilt", "1.0", "[1.2,)", true}, // object form is not evaluated
+		{"forge", "47.1.0", "[47,)", true},
+		{"forge", "46.0.0", "[47,)", false},
+		{"neoforge", "20.4.1", "[20.4,20.5)", true},
+	}
+	for _, tt := range tests {
+		if got := Satisfies(tt.format, tt.version, tt.rng); got != tt.want {
+			t.Errorf("Satisfies(%q, %q, %q) = %v, want %v", tt.format, tt.version, tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestMavenSatisfies(t *testing.T) {
+	tests := []struct {
+		v, rng string
+		want   bool
+	}{
+		{"1.5", "1.0", true}, // a bare version only recommends
+		{"1.0", "[1.0,2.0)", true},
+		{"2.0", "[1.0,2.0)", false},
+		{"2.0", "[1.0,2.0]", true},
+		{"1.0", "(1.0,2.0)", false},
+		{"1.20.1", "[1.20.1]", true},
+		{"1.20.2", "[1.20.1]", false},
+		{"0.9", "(,1.0],[1.2,)", true},
+		{"1.1", "(,1.0],[1.2,)", false},
+		{"1.3", "(,1.0],[1.2,)", true},
+		{"99", "[47,)", true},
+	}
+	for _, tt := range tests {
+		if got := mavenSatisfies(tt.v, tt.rng); got != tt.want {
+			t.Errorf("mavenSatisfies(%q, %q) = %v, want %v", tt.v, tt.rng, got, tt.want)
+		}
+	}
+}
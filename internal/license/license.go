@@ -0,0 +1,142 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PolicyFile sits next to project.json.
+const PolicyFile = "license-policy.json"
+
+// license classes, usable in a policy's allow and deny lists
+const (
+	Open        = ""            // a regular SPDX license
+	Proprietary = "proprietary" // all rights reserved
+	Custom      = "custom"      // LicenseRef-* other than ARR
+	Unknown     = "unknown"     // not declared
+)
+
+// @brief Classify sorts a license id into a class.
+// @param id SPDX id or LicenseRef as the provider reports it
+// @return Open, Proprietary, Custom or Unknown
+func Classify(id string) string {
+	l := strings.ToLower(strings.TrimSpace(id))
+	switch {
+	case l == "" || l == "licenseref-unknown":
+		return Unknown
+	case strings.Contains(l, "all-rights-reserved") || l == "arr" || l == "licenseref-arr" || strings.Contains(l, "proprietary"):
+		return Proprietary
+	case strings.HasPrefix(l, "licenseref-"):
+		return Custom
+	}
+	return Open
+}
+
+// Policy is license-policy.json: which licenses a pack may ship.
+type Policy struct {
+	// Allow lists SPDX ids, globs ("GPL-*") or classes; when set, nothing else is allowed.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists SPDX ids, globs or classes that are never allowed.
+	Deny []string `json:"deny,omitempty"`
+	// Exceptions are entry slugs allowed whatever their license (permission obtained).
+	Exceptions []string `json:"exceptions,omitempty"`
+	// OnViolation is "warn" (default) or "fail".
+	OnViolation string `json:"on_violation,omitempty"`
+}
+
+// @brief LoadPolicy reads license-policy.json from a pack folder.
+// @param dir folder holding project.json
+// @return policy, nil if there is no policy file, or error if it is invalid
+func LoadPolicy(dir string) (*Policy, error) {
+	b, err := os.ReadFile(filepath.Join(dir, PolicyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", PolicyFile, err)
+	}
+	switch p.OnViolation {
+	case "", "warn", "fail":
+	default:
+		return nil, fmt.Errorf("%s: on_violation must be \"warn\" or \"fail\"", PolicyFile)
+	}
+	return &p, nil
+}
+
+// @brief Fail reports whether violations should stop the command.
+func (p *Policy) Fail() bool {
+	return p != nil && p.OnViolation == "fail"
+}
+
+// @brief Check tests one entry's license against the policy.
+// An SPDX "A OR B" expression passes if any alternative does.
+// @param slug entry slug, for exceptions
+// @param id license id
+// @return why the license is not allowed, or "" if it is (or there is no policy)
+func (p *Policy) Check(slug, id string) string {
+	if p == nil {
+		return ""
+	}
+	for _, ex := range p.Exceptions {
+		if ex == slug {
+			return ""
+		}
+	}
+	var reason string
+	for _, alt := range alternatives(id) {
+		if reason = p.check(alt); reason == "" {
+			return ""
+		}
+	}
+	return reason
+}
+
+func (p *Policy) check(id string) string {
+	label := id
+	if label == "" {
+		label = Unknown
+	}
+	for _, pat := range p.Deny {
+		if matches(pat, id) {
+			return fmt.Sprintf("%s is denied", label)
+		}
+	}
+	if len(p.Allow) == 0 {
+		return ""
+	}
+	for _, pat := range p.Allow {
+		if matches(pat, id) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s is not in the allow list", label)
+}
+
+// matches compares a policy pattern with a license id, by class, glob or exact id.
+func matches(pat, id string) bool {
+	pat = strings.ToLower(strings.TrimSpace(pat))
+	switch pat {
+	case Proprietary, Custom, Unknown:
+		return Classify(id) == pat
+	}
+	ok, err := path.Match(pat, strings.ToLower(id))
+	return err == nil && ok
+}
+
+// alternatives splits "(MIT OR Apache-2.0)" into its ids; anything else is one id.
+func alternatives(id string) []string {
+	id = strings.Trim(strings.TrimSpace(id), "()")
+	parts := strings.Split(id, " OR ")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
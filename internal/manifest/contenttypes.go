@@ -0,0 +1,210 @@
+package manifest
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// ContentType describes one kind of project the manifest can hold.
+// Everything that differs between mods, resource packs, shaders, ... lives here,
+// so supporting a new kind only needs a new entry in contentTypes.
+type ContentType struct {
+	Name       string                      // modrinth project_type, also the URL segment ("mod")
+	Label      string                      // heading used in listings ("MODS")
+	Section    string                      // manifest section key ("mods")
+	Dest       string                      // default folder under the game dir ("mods")
+	Loaders    func(mc Minecraft) []string // loader filters to try in order, empty = any loader
+	Loose      bool                        // retry without the game version if nothing matches
+	Extensions []string                    // accepted file extensions, empty = any
+	PerWorld   bool                        // Dest is relative to each world, not the game dir
+	ClientOnly bool                        // only the game client loads it, server packs leave it out
+
+	// Detect claims projects whose project_type alone is ambiguous
+	// (Modrinth reports datapacks and plugins as "mod"). Optional, tried in
+	// registry order.
+	Detect func(prj *source.Project, mc Minecraft) bool
+}
+
+// registry, in the order sections are listed and saved
+var contentTypes = []ContentType{
+	{
+		Name:       "mod",
+		Label:      "MODS",
+		Section:    "mods",
+		Dest:       "mods",
+		Loaders:    func(mc Minecraft) []string { return []string{mc.Loader} },
+		Extensions: []string{".jar"},
+	},
+	{
+		Name:       "resourcepack",
+		Label:      "RESOURCE PACKS",
+		Section:    "resourcepacks",
+		Dest:       "resourcepacks",
+		Loaders:    anyLoader,
+		Loose:      true,
+		Extensions: []string{".zip"},
+		ClientOnly: true,
+	},
+	{
+		Name:       "shader",
+		Label:      "SHADERS",
+		Section:    "shaders",
+		Dest:       "shaderpacks",
+		Loaders:    anyLoader,
+		Loose:      true,
+		Extensions: []string{".zip"},
+		ClientOnly: true,
+	},
+	{
+		Name:       "plugin",
+		Label:      "PLUGINS",
+		Section:    "plugins",
+		Dest:       "plugins",
+		Loaders:    func(mc Minecraft) []string { return PluginPlatforms(mc.Loader) },
+		Extensions: []string{".jar"},
+		Detect: func(prj *source.Project, mc Minecraft) bool {
+			if !IsPluginPlatform(mc.Loader) {
+				return false
+			}
+			for _, p := range PluginPlatforms(mc.Loader) {
+				if contains(prj.Loaders, p) {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		Name:       "datapack",
+		Label:      "DATAPACKS",
+		Section:    "datapacks",
+		Dest:       "datapacks",
+		Loaders:    func(Minecraft) []string { return []string{"datapack"} },
+		Extensions: []string{".zip"},
+		PerWorld:   true,
+		Detect: func(prj *source.Project, mc Minecraft) bool {
+			return contains(prj.Loaders, "datapack") && !contains(prj.Loaders, mc.Loader)
+		},
+	},
+}
+
+func anyLoader(Minecraft) []string { return nil }
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// @brief ContentTypes lists every registered content type.
+// @return registered types in section order
+func ContentTypes() []ContentType {
+	return contentTypes
+}
+
+// @brief TypeForProject looks up the content type for a Modrinth project_type.
+// @param projectType e.g. "mod", "resourcepack"
+// @return the content type and true, or false if unsupported
+func TypeForProject(projectType string) (ContentType, bool) {
+	for _, ct := range contentTypes {
+		if ct.Name == projectType {
+			return ct, true
+		}
+	}
+	return ContentType{}, false
+}
+
+// @brief TypeOf picks the content type for a project as seen by this instance.
+// Types with a Detect rule are tried first, then the project_type is matched.
+// @param prj project or search hit
+// @param mc Minecraft version and loader of the instance
+// @return the content type and true, or false if unsupported
+func TypeOf(prj *source.Project, mc Minecraft) (ContentType, bool) {
+	for _, ct := range contentTypes {
+		if ct.Detect != nil && ct.Detect(prj, mc) {
+			return ct, true
+		}
+	}
+	return TypeForProject(prj.ProjectType)
+}
+
+// @brief TypeForSection looks up the content type stored in a manifest section.
+// @param section e.g. "mods", "shaders"
+// @return the content type and true, or false if unknown
+func TypeForSection(section string) (ContentType, bool) {
+	for _, ct := range contentTypes {
+		if ct.Section == section {
+			return ct, true
+		}
+	}
+	return ContentType{}, false
+}
+
+// @brief TypeForPath works out the content type of a file from the folder it sits in.
+// Files outside every known folder are treated as mods with a custom destination.
+// @param p slash path relative to the game dir ("mods/sodium.jar", "saves/w/datapacks/x.zip")
+// @return content type, destination folder and, for per-world types, the world
+func TypeForPath(p string) (ContentType, string, string) {
+	dir := path.Dir(p)
+	for _, ct := range contentTypes {
+		switch {
+		case ct.PerWorld && path.Base(dir) == ct.Dest && path.Dir(dir) != ".":
+			return ct, ct.Dest, path.Dir(dir)
+		case !ct.PerWorld && dir == ct.Dest:
+			return ct, ct.Dest, ""
+		}
+	}
+	ct, _ := TypeForProject("mod")
+	return ct, dir, ""
+}
+
+// @brief Accepts reports whether a file name has one of the type's extensions.
+// A trailing .disabled suffix is ignored.
+// @param name file name
+// @return true if the extension matches or the type accepts anything
+func (ct ContentType) Accepts(name string) bool {
+	if len(ct.Extensions) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(name, ".disabled")))
+	for _, want := range ct.Extensions {
+		if ext == want {
+			return true
+		}
+	}
+	return false
+}
+
+// @brief Versions lists compatible versions of a project, newest first,
+// applying the type's loader filters and loose fallback.
+// @param ctx context for API calls
+// @param p provider hosting the project
+// @param id project id or slug
+// @param mc Minecraft version and loader of the instance
+// @return compatible versions (possibly empty) or error
+func (ct ContentType) Versions(ctx context.Context, p source.Provider, id string, mc Minecraft) ([]source.Version, error) {
+	loaders := ct.Loaders(mc)
+	if len(loaders) == 0 {
+		loaders = []string{""}
+	}
+	for _, loader := range loaders {
+		vers, err := p.ListVersions(ctx, id, source.Filter{GameVersion: mc.Version, Loader: loader})
+		if err != nil {
+			return nil, err
+		}
+		if len(vers) > 0 {
+			return vers, nil
+		}
+	}
+	if ct.Loose {
+		return p.ListVersions(ctx, id, source.Filter{})
+	}
+	return nil, nil
+}
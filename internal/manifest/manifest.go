@@ -1,17 +1,18 @@
 package manifest
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha1"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/silask7188/ModrinthCLI/internal/modrinth"
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+	"github.com/silask7188/ModrinthCLI/internal/source"
 )
 
 const Version = 1
@@ -30,6 +31,7 @@ func Load(path string) (*Manifest, error) {
 	}
 	m.path = path
 	m.baseDir = filepath.Dir(path)
+	m.hashes = openHashes(m.baseDir)
 	return &m, nil
 }
 
@@ -41,145 +43,313 @@ func New(path string, mc Minecraft) *Manifest {
 	return &Manifest{
 		Schema:    Version,
 		Minecraft: mc,
+		sections:  make(map[string][]Entry),
 		path:      path,
 		baseDir:   filepath.Dir(path),
+		hashes:    openHashes(filepath.Dir(path)),
 	}
 }
 
-// @brief save the manifest to disk
+// @brief openHashes opens the hash index in a game dir's state folder.
+func openHashes(dir string) *checksum.Index {
+	return checksum.OpenIndex(dir, filepath.Join(dir, overrides.StateDir, checksum.IndexFile))
+}
+
+// @brief save the manifest and its hash index to disk
 // @return error if saving failed
 func (m *Manifest) Save() error {
 	if m.path == "" {
 		return errors.New("Manifest path unset")
 	}
 	b, _ := json.MarshalIndent(m, "", " ")
-	return os.WriteFile(m.path, b, 0o644)
+	if err := os.WriteFile(m.path, b, 0o644); err != nil {
+		return err
+	}
+	return m.hashes.Save()
 }
 
-// @brief add a new entry to the manifest
-// @param ctx context for API calls
-// @param slug modrinth project slug
-// @param dest destination folder (mods, resourcepacks, shaders)
-// @return error if the project was not found or could not be added
-func (m *Manifest) Add(ctx context.Context, slug, dest string) error {
-	cli, err := modrinth.New("https://api.modrinth.com/v2/")
+// @brief MarshalJSON writes the header fields followed by every non-empty section,
+// in registry order.
+// @return encoded manifest or error
+func (m Manifest) MarshalJSON() ([]byte, error) {
+	type alias Manifest // header fields only, no methods
+	head, err := json.Marshal(alias(m))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	prj, err := cli.GetProject(ctx, modrinth.ProjectQuery{Slug: slug})
-	if err != nil {
-		return fmt.Errorf("modrinth project %q not found: %w", slug, err)
+	var buf bytes.Buffer
+	buf.Write(head[:len(head)-1]) // drop closing brace
+	for _, ct := range contentTypes {
+		entries := m.sections[ct.Section]
+		if len(entries) == 0 {
+			continue
+		}
+		b, err := json.Marshal(entries)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, ",%q:", ct.Section)
+		buf.Write(b)
 	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
 
-	if dest == "" {
-		switch prj.ProjectType {
-		case "mod":
-			dest = "mods"
-		case "resourcepack":
-			dest = "resourcepacks"
-		case "shader":
-			dest = "shaderpacks"
-		default:
-			return fmt.Errorf("cannot infer destination for project type %q; use --to flag", prj.ProjectType)
+// @brief UnmarshalJSON reads the header fields and every registered section.
+// @param data encoded manifest
+// @return error if the JSON is malformed
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	type alias Manifest
+	var head alias
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = Manifest(head)
+	m.sections = make(map[string][]Entry)
+	for _, ct := range contentTypes {
+		b, ok := raw[ct.Section]
+		if !ok {
+			continue
+		}
+		var entries []Entry
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return fmt.Errorf("section %q: %w", ct.Section, err)
+		}
+		if len(entries) > 0 {
+			m.sections[ct.Section] = entries
 		}
 	}
+	return nil
+}
 
-	gameVer := m.Minecraft.Version
-	loader := m.Minecraft.Loader
-	needLooseSearch := prj.ProjectType == "resourcepack" || prj.ProjectType == "shader"
+// @brief Path returns the manifest file's path.
+func (m *Manifest) Path() string {
+	return m.path
+}
+
+// @brief Dir returns the folder the manifest lives in.
+// Local file sources are resolved relative to it.
+func (m *Manifest) Dir() string {
+	return m.baseDir
+}
+
+// @brief Hashes returns the index of file hashes in the game dir, shared by
+// everything that identifies files by checksum. Save writes it.
+func (m *Manifest) Hashes() *checksum.Index {
+	return m.hashes
+}
 
-	vers, err := cli.ProjectVersions(ctx, slug, gameVer, func() string {
-		if needLooseSearch && dest != "mods" {
-			return "" // ignore loader for packs/shaders
+// @brief Entries returns the entries of one content type.
+// The slice shares storage with the manifest, so entries may be edited in place.
+// @param ct content type
+// @return entries of that section (may be empty)
+func (m *Manifest) Entries(ct ContentType) []Entry {
+	return m.sections[ct.Section]
+}
+
+// @brief Find looks up an entry by slug in every section.
+// @param slug modrinth project slug
+// @return the entry's content type and a pointer to it, or nil if not found
+func (m *Manifest) Find(slug string) (ContentType, *Entry) {
+	for _, ct := range contentTypes {
+		entries := m.sections[ct.Section]
+		for i := range entries {
+			if entries[i].Slug == slug {
+				return ct, &entries[i]
+			}
 		}
-		return loader
-	}())
+	}
+	return ContentType{}, nil
+}
+
+// @brief checkSlug keeps slugs unique across the manifest: Find, Remove and
+// enable/disable look entries up by slug alone.
+// @param ct content type the entry is going into
+// @param slug entry name
+// @param src source of the new entry ("" for Modrinth)
+// @return error if another source or content type already uses the slug
+func (m *Manifest) checkSlug(ct ContentType, slug, src string) error {
+	have, e := m.Find(slug)
+	if e == nil || (e.Source == src && have.Section == ct.Section) {
+		return nil
+	}
+	return fmt.Errorf("%s is already in the manifest as a %s %s", slug, e.SourceName(), have.Name)
+}
+
+// @brief add a new entry to the manifest
+// @param ctx context for API calls
+// @param p provider hosting the project (Modrinth, CurseForge)
+// @param id project slug or id
+// @param dest destination folder, empty to use the content type default
+// @return the slug the entry was stored under, or error if the project was not found or could not be added
+func (m *Manifest) Add(ctx context.Context, p source.Provider, id, dest string) (string, error) {
+	prj, err := p.LookupProject(ctx, id)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("%s project %q not found: %w", p.Name(), id, err)
 	}
 
-	if len(vers) == 0 && needLooseSearch {
-		vers, err = cli.ProjectVersions(ctx, slug, "", "")
-		if err != nil {
-			return err
-		}
+	if prj.ProjectType == "modpack" {
+		return "", fmt.Errorf("%s is a modpack, create an instance from it with 'mod init --from %s'", prj.Slug, prj.Slug)
+	}
+	ct, ok := TypeOf(prj, m.Minecraft)
+	if !ok {
+		return "", fmt.Errorf("unknown project type %q for %q", prj.ProjectType, id)
+	}
+	if dest == "" {
+		dest = ct.Dest
+	}
+	if !filepath.IsLocal(filepath.FromSlash(dest)) {
+		return "", fmt.Errorf("folder %q is outside the game dir", dest)
 	}
 
+	vers, err := ct.Versions(ctx, p, prj.ID, m.Minecraft)
+	if err != nil {
+		return "", err
+	}
 	if len(vers) == 0 {
-		return fmt.Errorf("no compatible versions for %s (MC=%s loader=%s)",
-			slug, gameVer, loader)
+		return "", fmt.Errorf("no compatible versions for %s (MC=%s loader=%s)",
+			prj.Slug, m.Minecraft.Version, m.Minecraft.Loader)
 	}
 	latest := vers[0] // newest -> oldest
 
-	switch prj.ProjectType {
-	case "mod":
-		for i := range m.Mods {
-			if m.Mods[i].Slug == slug {
-				m.Mods[i].Dest = "mods"
-				m.Mods[i].Version = latest.ID
-				m.Mods[i].VersionNumber = latest.VersionNumber
-				m.Mods[i].Enable = true
-				return nil
-			}
+	src := p.Name()
+	if src == source.Modrinth {
+		src = "" // keep Modrinth entries as they always were
+	}
+
+	if err := m.checkSlug(ct, prj.Slug, src); err != nil {
+		return "", fmt.Errorf("%w, remove it first with 'mod remove %s'", err, prj.Slug)
+	}
+	entries := m.sections[ct.Section]
+	for i := range entries {
+		if entries[i].Slug == prj.Slug && entries[i].Source == src {
+			entries[i].Dest = dest
+			entries[i].Version = latest.ID
+			entries[i].VersionNumber = latest.Number
+			entries[i].Enable = true
+			entries[i].ProjectID = prj.ID
+			entries[i].ClientSide = prj.ClientSide
+			entries[i].ServerSide = prj.ServerSide
+			entries[i].Modpack = false // explicitly added, no longer pinned
+			entries[i].Pinned = false
+			return prj.Slug, nil
 		}
-		// Not found, add new
-		m.Mods = append(m.Mods, Entry{
-			Slug:          slug,
-			Dest:          "mods",
-			Version:       latest.ID,
-			VersionNumber: latest.VersionNumber,
-			Enable:        true,
-		})
-		return nil
-	case "resourcepack":
-		for i := range m.ResourcePacks {
-			if m.ResourcePacks[i].Slug == slug {
-				m.ResourcePacks[i].Dest = "resourcepacks"
-				m.ResourcePacks[i].Version = latest.ID
-				m.ResourcePacks[i].VersionNumber = latest.VersionNumber
-				m.ResourcePacks[i].Enable = true
-				return nil
-			}
+	}
+	// Not found, add new
+	m.sections[ct.Section] = append(entries, Entry{
+		Slug:          prj.Slug,
+		Version:       latest.ID,
+		VersionNumber: latest.Number,
+		Dest:          dest,
+		Enable:        true,
+		ClientSide:    prj.ClientSide,
+		ServerSide:    prj.ServerSide,
+		ProjectID:     prj.ID,
+		Source:        src,
+	})
+	return prj.Slug, nil
+}
+
+// @brief AddEntry adds or replaces an entry built by the caller: custom sources,
+// or provider entries pinned to a version (modpack imports).
+// @param ct content type deciding the section
+// @param e entry with its Source fields filled in
+// @return error if the source is incomplete
+func (m *Manifest) AddEntry(ct ContentType, e Entry) error {
+	if e.Slug == "" {
+		return errors.New("entry needs a name")
+	}
+	switch e.Source {
+	case source.URL:
+		if err := source.CheckURL(e.URL); err != nil {
+			return err
 		}
-		m.ResourcePacks = append(m.ResourcePacks, Entry{
-			Slug:          slug,
-			Dest:          "resourcepacks",
-			Version:       latest.ID,
-			VersionNumber: latest.VersionNumber,
-			Enable:        true,
-		})
-		return nil
-	case "shader":
-		for i := range m.Shaders {
-			if m.Shaders[i].Slug == slug {
-				m.Shaders[i].Dest = "shaderpacks"
-				m.Shaders[i].Version = latest.ID
-				m.Shaders[i].VersionNumber = latest.VersionNumber
-				m.Shaders[i].Enable = true
-				return nil
-			}
+		if e.Checksum == "" {
+			return fmt.Errorf("url entry %s needs a sha1 hash", e.Slug)
+		}
+	case source.File:
+		if e.Path == "" {
+			return fmt.Errorf("file entry %s needs a path", e.Slug)
+		}
+	case source.GitHub:
+		if e.Repo == "" {
+			return fmt.Errorf("github entry %s needs a repo", e.Slug)
+		}
+	case source.Maven:
+		if _, err := source.ParseCoords(e.Maven); err != nil {
+			return err
+		}
+		if e.Repository == "" {
+			return fmt.Errorf("maven entry %s needs a repository url", e.Slug)
+		}
+		if !strings.HasPrefix(e.Repository, "https://") {
+			return fmt.Errorf("maven repository %q of %s must be an https url", e.Repository, e.Slug)
+		}
+	case "", source.CurseForge:
+		if e.Version == "" {
+			return fmt.Errorf("%s entry %s needs a version", e.SourceName(), e.Slug)
 		}
-		m.Shaders = append(m.Shaders, Entry{
-			Slug:          slug,
-			Dest:          "shaderpacks",
-			Version:       latest.ID,
-			VersionNumber: latest.VersionNumber,
-			Enable:        true,
-		})
-		return nil
 	default:
-		return fmt.Errorf("unknown project type %q for slug %q", prj.ProjectType, slug)
+		return fmt.Errorf("unknown source %q for %s", e.Source, e.Slug)
+	}
+	if e.Dest == "" {
+		e.Dest = ct.Dest
+	}
+	if !filepath.IsLocal(filepath.FromSlash(e.Dest)) {
+		return fmt.Errorf("folder %q of %s is outside the game dir", e.Dest, e.Slug)
 	}
+	for _, w := range e.Worlds {
+		if !filepath.IsLocal(filepath.FromSlash(w)) {
+			return fmt.Errorf("world %q of %s is outside the game dir", w, e.Slug)
+		}
+	}
+	if e.Filename != "" && (!filepath.IsLocal(e.Filename) || strings.ContainsAny(e.Filename, `/\`)) {
+		return fmt.Errorf("filename %q of %s is not a plain file name", e.Filename, e.Slug)
+	}
+
+	e.Checksum, e.SHA512 = strings.ToLower(e.Checksum), strings.ToLower(e.SHA512)
+	if err := e.Sums().Validate(); err != nil {
+		return fmt.Errorf("%s: %w", e.Slug, err)
+	}
+
+	if err := m.checkSlug(ct, e.Slug, e.Source); err != nil {
+		return fmt.Errorf("%w, pass --name to add it under another name", err)
+	}
+	entries := m.sections[ct.Section]
+	for i := range entries {
+		if entries[i].Slug == e.Slug {
+			entries[i] = e
+			return nil
+		}
+	}
+	m.sections[ct.Section] = append(entries, e)
+	return nil
+}
+
+// @brief TargetSide returns the side this instance is installed as.
+// @return SideServer if configured, otherwise SideClient
+func (m *Manifest) TargetSide() string {
+	if m.Side == SideServer {
+		return SideServer
+	}
+	return SideClient
 }
 
 // @brief get all enabled entries in the manifest
 // @return slice of enabled entries
 func (m *Manifest) Enabled() []Entry {
-	out := make([]Entry, 0, len(m.Mods))
-	for _, e := range m.Mods {
-		if e.Enable {
-			out = append(out, e)
+	var out []Entry
+	for _, ct := range contentTypes {
+		for _, e := range m.sections[ct.Section] {
+			if e.Enable {
+				out = append(out, e)
+			}
 		}
 	}
 	return out
@@ -189,46 +359,52 @@ func (m *Manifest) Enabled() []Entry {
 // Enable / Disable – rename file or folder on disk
 // -------------------------------------------------------------------
 
-// @brief Enable turns a mod on (removes .disabled suffix so game sees it).
+// @brief Enable turns an entry on (removes .disabled suffix so game sees it).
 // @param gameDir path to the game directory
 // @param slug modrinth project slug
-// @return error if the mod was not found or could not be enabled
+// @return error if the entry was not found or could not be enabled
 func (m *Manifest) Enable(gameDir, slug string) error {
-	return toggleDisabled(gameDir, m.Mods, slug, true)
+	return m.toggleDisabled(gameDir, slug, true)
 }
 
-// @brief Disable turns a mod off (adds .disabled suffix so game ignores it).
+// @brief Disable turns an entry off (adds .disabled suffix so game ignores it).
 // @param gameDir path to the game directory
 // @param slug modrinth project slug
-// @return error if the mod was not found or could not be disabled
+// @return error if the entry was not found or could not be disabled
 func (m *Manifest) Disable(gameDir, slug string) error {
-	return toggleDisabled(gameDir, m.Mods, slug, false)
+	return m.toggleDisabled(gameDir, slug, false)
 }
 
-// @brief toggleDisabled enables or disables a mod by renaming its file or folder.
+// @brief toggleDisabled enables or disables an entry by renaming its file or folder.
+// Per-world entries are toggled in every world that has a copy.
 // @param gameDir path to the game directory
-// @param mods slice of manifest entries
 // @param slug modrinth project slug
 // @param wantEnable true to enable, false to disable
-// @return error if the mod was not found or could not be toggled
-func toggleDisabled(gameDir string, entries []Entry, slug string, wantEnable bool) error {
-	for i := range entries {
-		ent := &entries[i]
-		if ent.Slug != slug {
-			continue
-		}
-		ent.Enable = wantEnable
+// @return error if the entry was not found or could not be toggled
+func (m *Manifest) toggleDisabled(gameDir, slug string, wantEnable bool) error {
+	ct, ent := m.Find(slug)
+	if ent == nil {
+		return fmt.Errorf("slug %s not in manifest", slug)
+	}
+	ent.Enable = wantEnable
 
-		dir := filepath.Join(gameDir, ent.Dest)
+	dirs := Dirs(gameDir, ct, *ent)
+	if len(dirs) == 0 {
+		return fmt.Errorf("no worlds found for %s", slug)
+	}
+
+	toggled := 0
+	var missing error
+	for _, dir := range dirs {
 		filename := ent.Filename
 
 		// fallback: scan directory for a file with matching checksum
 		if filename == "" {
-			found, err := findFileByChecksum(dir, ent.Checksum)
+			found, err := m.hashes.Find(dir, ent.Sums(), ct.Accepts)
 			if err != nil {
 				return fmt.Errorf("no filename recorded for %s and no match by checksum: %w", slug, err)
 			}
-			filename = found
+			filename = strings.TrimSuffix(found, ".disabled")
 			ent.Filename = filename // optional: heal the manifest
 		}
 
@@ -243,38 +419,34 @@ func toggleDisabled(gameDir string, entries []Entry, slug string, wantEnable boo
 		}
 
 		if _, err := os.Stat(from); os.IsNotExist(err) {
-			return fmt.Errorf("file %s not found; expected %s", slug, from)
+			missing = fmt.Errorf("file %s not found; expected %s", slug, from)
+			continue // a world may not have a copy yet
 		}
 
 		if err := os.Rename(from, to); err != nil {
 			return fmt.Errorf("failed to toggle %s: %w", slug, err)
 		}
-
-		return nil
+		toggled++
+	}
+	if toggled == 0 {
+		return missing
 	}
-	return fmt.Errorf("slug %s not in manifest", slug)
+	return nil
 }
 
 // @brief remove an item from a specific section of the manifest.
-// @param section the section to remove from (mods, resourcepacks, shaders)
+// @param section the section to remove from (mods, resourcepacks, shaders, ...)
 // @param slug modrinth project slug
 // @return error if the item was not found or could not be removed
 func (m *Manifest) RemoveFromSection(section string, slug string) error {
-	var mods *[]Entry
-	switch section {
-	case "mods":
-		mods = &m.Mods
-	case "resourcepacks":
-		mods = &m.ResourcePacks
-	case "shaders":
-		mods = &m.Shaders
-	default:
+	if _, ok := TypeForSection(section); !ok {
 		return fmt.Errorf("unknown section %q", section)
 	}
 
-	for i := range *mods {
-		if (*mods)[i].Slug == slug {
-			*mods = append((*mods)[:i], (*mods)[i+1:]...)
+	entries := m.sections[section]
+	for i := range entries {
+		if entries[i].Slug == slug {
+			m.sections[section] = append(entries[:i], entries[i+1:]...)
 			return nil
 		}
 	}
@@ -286,104 +458,33 @@ func (m *Manifest) RemoveFromSection(section string, slug string) error {
 // @param slug modrinth project slug
 // @return error if the entry was not found or could not be removed
 func (m *Manifest) Remove(gameDir, slug string) error {
-	sections := []struct {
-		name    string
-		entries *[]Entry
-	}{
-		{"mods", &m.Mods},
-		{"resourcepacks", &m.ResourcePacks},
-		{"shaders", &m.Shaders},
-	}
-
-	for _, sec := range sections {
-		for i := range *sec.entries {
-			if (*sec.entries)[i].Slug != slug {
-				continue
-			}
-
-			entry := (*sec.entries)[i]
-
-			// Resolve filename
-			filename := entry.Filename
-			if filename == "" {
-				found, err := findFileByChecksum(filepath.Join(gameDir, entry.Dest), entry.Checksum)
-				if err != nil {
-					return fmt.Errorf("could not find file for %s by filename or checksum: %w", slug, err)
-				}
-				filename = found
-				entry.Filename = found
-			}
-
-			// Delete file from disk
-			path := filepath.Join(gameDir, entry.Dest, filename)
-			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-				return fmt.Errorf("failed to remove file %q: %w", path, err)
-			}
-
-			// Remove from manifest
-			*sec.entries = append((*sec.entries)[:i], (*sec.entries)[i+1:]...)
-			return nil
-		}
+	ct, entry := m.Find(slug)
+	if entry == nil {
+		return fmt.Errorf("slug %s not found in any section", slug)
 	}
 
-	return fmt.Errorf("slug %s not found in any section", slug)
-}
-
-func findFileByChecksum(dir, wantHash string) (string, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return "", err
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		path := filepath.Join(dir, entry.Name())
-		ok, err := fileExistsWithSHA1(path, wantHash)
-		if err != nil {
-			return "", err
-		}
-		if ok {
-			return entry.Name(), nil
+	for _, dir := range Dirs(gameDir, ct, *entry) {
+		// Resolve filename
+		filename := entry.Filename
+		if filename == "" {
+			found, err := m.hashes.Find(dir, entry.Sums(), ct.Accepts)
+			if err != nil {
+				return fmt.Errorf("could not find file for %s by filename or checksum: %w", slug, err)
+			}
+			filename = found
 		}
-	}
-	return "", fmt.Errorf("no file with checksum %s found in %s", wantHash, dir)
-}
 
-// @brief computeSHA1 computes the SHA1 hash of a file.
-func computeSHA1(path string) (string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-	h := sha1.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(h.Sum(nil)), nil
-}
-
-// @brief fileExistsWithSHA1 checks if a file exists and matches the given SHA1 hash.
-// @param path file path to check
-// @param want expected SHA1 hash
-// @return true if file exists and matches, false if not, or error if any
-func fileExistsWithSHA1(path, want string) (bool, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
+		// Delete file from disk, enabled or not
+		path := filepath.Join(dir, strings.TrimSuffix(filename, ".disabled"))
+		for _, p := range []string{path, path + ".disabled"} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove file %q: %w", p, err)
+			}
 		}
-		return false, err
 	}
-	defer f.Close()
 
-	h := sha1.New()
-	if _, err = io.Copy(h, f); err != nil {
-		return false, err
-	}
-	return hex.EncodeToString(h.Sum(nil)) == want, nil
+	// Remove from manifest
+	return m.RemoveFromSection(ct.Section, slug)
 }
 
 // @brief check for filename updates by comparing sha1 hashes.
@@ -393,65 +494,67 @@ func (m *Manifest) CheckFilenames() ([]string, error) {
 	var mismatches []string
 	var changed bool
 
-	check := func(entries *[]Entry) {
-		for i := range *entries {
-			ent := &(*entries)[i]
+	check := func(ct ContentType, entries []Entry) {
+		for i := range entries {
+			ent := &entries[i]
 
 			if ent.Filename == "" {
 				mismatches = append(mismatches, fmt.Sprintf("%s: no filename recorded, may not exist yet\n", ent.Slug))
 				continue
 			}
 
-			dir := filepath.Join(m.baseDir, ent.Dest)
-			expected := filepath.Join(dir, ent.Filename)
+			for _, dir := range Dirs(m.baseDir, ct, *ent) {
+				expected := filepath.Join(dir, ent.Filename)
 
-			// check if the expected file matches the checksum ! yipee
-			ok, err := fileExistsWithSHA1(expected, ent.Checksum)
-			if err != nil {
-				mismatches = append(mismatches, fmt.Sprintf("%s: %s\n", ent.Slug, err))
-				continue
-			}
-			if ok {
-				continue // all good
-			}
+				// check if the expected file matches the checksum ! yipee
+				ok, err := m.hashes.Matches(expected, ent.Sums())
+				if err != nil {
+					mismatches = append(mismatches, fmt.Sprintf("%s: %s\n", ent.Slug, err))
+					continue
+				}
+				if ok {
+					continue // all good
+				}
 
-			// try to find a file in the folder with the right checksum
-			foundName, err := findFileByChecksum(dir, ent.Checksum)
-			if err == nil {
-				mismatches = append(mismatches,
-					fmt.Sprintf("%s: filename changed from %s to %s\n", ent.Slug, ent.Filename, foundName))
-				ent.Filename = foundName
-				changed = true
-				continue
-			}
+				// try to find a file in the folder with the right checksum
+				foundName, err := m.hashes.Find(dir, ent.Sums(), ct.Accepts)
+				if err == nil {
+					mismatches = append(mismatches,
+						fmt.Sprintf("%s: filename changed from %s to %s\n", ent.Slug, ent.Filename, foundName))
+					ent.Filename = foundName
+					changed = true
+					continue
+				}
 
-			// if the original file exists, but has a different checksum !! uh oh!! unless not a mod
-			if _, err := os.Stat(expected); err == nil {
-				actual, err := computeSHA1(expected)
-				if err != nil {
-					mismatches = append(mismatches, fmt.Sprintf("%s: failed to hash %s: %s\n", ent.Slug, expected, err))
+				// if the original file exists, but has a different checksum !! uh oh!! unless not a mod
+				if _, err := os.Stat(expected); err == nil {
+					actual, err := m.hashes.File(expected)
+					if err != nil {
+						mismatches = append(mismatches, fmt.Sprintf("%s: failed to hash %s: %s\n", ent.Slug, expected, err))
+					} else if err := ent.Sums().Check(actual); err != nil {
+						mismatches = append(mismatches,
+							fmt.Sprintf("%s: %s %s\n", ent.Slug, ent.Filename, err))
+					}
 				} else {
+					// file not found at all, and not recoverable by hash
 					mismatches = append(mismatches,
-						fmt.Sprintf("%s: %s checksum mismatch (want %s, got %s)\n",
-							ent.Slug, ent.Filename, ent.Checksum, actual))
+						fmt.Sprintf("%s: file %s not found and no match by checksum\n", ent.Slug, expected))
 				}
-			} else {
-				// file not found at all, and not recoverable by hash
-				mismatches = append(mismatches,
-					fmt.Sprintf("%s: file %s not found and no match by checksum\n", ent.Slug, expected))
 			}
 		}
 	}
 
-	check(&m.Mods)
-	check(&m.ResourcePacks)
-	check(&m.Shaders)
+	for _, ct := range contentTypes {
+		check(ct, m.sections[ct.Section])
+	}
 
 	if changed {
 		err := m.Save()
 		if err != nil {
 			return nil, fmt.Errorf("failed to save manifest after filename updates: %w", err)
 		}
+	} else if err := m.hashes.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save hash index: %w", err)
 	}
 
 	if len(mismatches) > 0 {
@@ -0,0 +1,147 @@
+package manifest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	m := &Manifest{
+		Schema:    Version,
+		Minecraft: Minecraft{Loader: "fabric", LoaderVersion: "0.15.7", Version: "1.20.1"},
+		Side:      SideServer,
+		Modpack:   &Modpack{Name: "Pack", VersionNumber: "1.0"},
+		sections: map[string][]Entry{
+			"mods":      {{Slug: "sodium", Version: "v1", Dest: "mods", Checksum: "aa", Enable: true}},
+			"datapacks": {{Slug: "terralith", Version: "v2", Dest: "datapacks", Worlds: []string{"world"}}},
+		},
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sections follow the header in registry order, empty ones are left out
+	s := string(b)
+	if i, j := strings.Index(s, `"mods"`), strings.Index(s, `"datapacks"`); i < strings.Index(s, `"modpack"`) || j < i {
+		t.Errorf("section order: %s", s)
+	}
+	if strings.Contains(s, `"shaders"`) {
+		t.Errorf("empty section written: %s", s)
+	}
+
+	var got Manifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Schema != m.Schema || got.Minecraft != m.Minecraft || got.Side != m.Side || !reflect.DeepEqual(got.Modpack, m.Modpack) {
+		t.Errorf("header = %+v, want %+v", got, *m)
+	}
+	if !reflect.DeepEqual(got.sections, m.sections) {
+		t.Errorf("sections = %+v, want %+v", got.sections, m.sections)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string][]Entry
+		wantErr bool
+	}{
+		{
+			name: "unknown sections are ignored",
+			in:   `{"schema":1,"minecraft":{},"mods":[{"slug":"a"}],"gadgets":[{"slug":"b"}]}`,
+			want: map[string][]Entry{"mods": {{Slug: "a"}}},
+		},
+		{
+			name: "empty sections are dropped",
+			in:   `{"schema":1,"minecraft":{},"shaders":[]}`,
+			want: map[string][]Entry{},
+		},
+		{name: "malformed section", in: `{"schema":1,"mods":{"slug":"a"}}`, wantErr: true},
+		{name: "malformed json", in: `{"schema":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Manifest
+			err := json.Unmarshal([]byte(tt.in), &m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(m.sections, tt.want) {
+				t.Errorf("sections = %+v, want %+v", m.sections, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddEntrySlugCollision(t *testing.T) {
+	mods, _ := TypeForSection("mods")
+	rps, _ := TypeForSection("resourcepacks")
+	m := New(t.TempDir()+"/project.json", Minecraft{Version: "1.20.1", Loader: "fabric"})
+	if err := m.AddEntry(mods, Entry{Slug: "sodium", Version: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ct      ContentType
+		e       Entry
+		wantErr bool
+	}{
+		{name: "same source replaces", ct: mods, e: Entry{Slug: "sodium", Version: "v2"}},
+		{name: "other source", ct: mods, e: Entry{Slug: "sodium", Source: "file", Path: "sodium.jar"}, wantErr: true},
+		{name: "other content type", ct: rps, e: Entry{Slug: "sodium", Version: "v3"}, wantErr: true},
+		{name: "other name", ct: mods, e: Entry{Slug: "sodium-local", Source: "file", Path: "sodium.jar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.AddEntry(tt.ct, tt.e)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, e := m.Find("sodium"); e == nil || e.Version != "v2" || e.Source != "" {
+		t.Errorf("sodium = %+v, want the replaced Modrinth entry", e)
+	}
+}
+
+func TestAddEntryPaths(t *testing.T) {
+	mods, _ := TypeForSection("mods")
+	dps, _ := TypeForSection("datapacks")
+	tests := []struct {
+		name    string
+		ct      ContentType
+		e       Entry
+		wantErr bool
+	}{
+		{name: "default folder", ct: mods, e: Entry{Slug: "a", Version: "v1", Filename: "a.jar"}},
+		{name: "custom folder", ct: mods, e: Entry{Slug: "b", Version: "v1", Dest: "config/b", Filename: "b.jar"}},
+		{name: "folder outside", ct: mods, e: Entry{Slug: "c", Version: "v1", Dest: "../..", Filename: "c.jar"}, wantErr: true},
+		{name: "absolute folder", ct: mods, e: Entry{Slug: "d", Version: "v1", Dest: "/etc", Filename: "d.jar"}, wantErr: true},
+		{name: "filename with a path", ct: mods, e: Entry{Slug: "e", Version: "v1", Filename: "../e.jar"}, wantErr: true},
+		{name: "filename dots", ct: mods, e: Entry{Slug: "f", Version: "v1", Filename: ".."}, wantErr: true},
+		{name: "world outside", ct: dps, e: Entry{Slug: "g", Version: "v1", Worlds: []string{"../w"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(t.TempDir()+"/project.json", Minecraft{Version: "1.20.1", Loader: "fabric"})
+			err := m.AddEntry(tt.ct, tt.e)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientOnly(t *testing.T) {
+	want := map[string]bool{"mods": false, "resourcepacks": true, "shaders": true, "plugins": false, "datapacks": false}
+	for _, ct := range ContentTypes() {
+		if ct.ClientOnly != want[ct.Section] {
+			t.Errorf("%s: ClientOnly = %v, want %v", ct.Section, ct.ClientOnly, want[ct.Section])
+		}
+	}
+}
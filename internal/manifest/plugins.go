@@ -0,0 +1,32 @@
+package manifest
+
+// plugin platforms, each mapped to the platforms whose plugins it can load,
+// best match first (a Purpur server runs Paper, Spigot and Bukkit plugins)
+var pluginPlatforms = map[string][]string{
+	"purpur":     {"purpur", "paper", "spigot", "bukkit"},
+	"paper":      {"paper", "spigot", "bukkit"},
+	"spigot":     {"spigot", "bukkit"},
+	"bukkit":     {"bukkit"},
+	"folia":      {"folia"}, // regionised threading, plugins must opt in
+	"velocity":   {"velocity"},
+	"waterfall":  {"waterfall", "bungeecord"},
+	"bungeecord": {"bungeecord"},
+}
+
+// @brief IsPluginPlatform reports whether a loader is a plugin server platform.
+// @param loader manifest loader, e.g. "paper"
+// @return true for paper, purpur, spigot, bukkit, folia, velocity, ...
+func IsPluginPlatform(loader string) bool {
+	_, ok := pluginPlatforms[loader]
+	return ok
+}
+
+// @brief PluginPlatforms returns the platforms to try when resolving plugins.
+// @param loader manifest loader, e.g. "purpur"
+// @return fallback chain, best first; just the loader itself if it is not a known platform
+func PluginPlatforms(loader string) []string {
+	if chain, ok := pluginPlatforms[loader]; ok {
+		return chain
+	}
+	return []string{loader}
+}
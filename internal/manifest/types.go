@@ -1,13 +1,39 @@
 package manifest
 
+import (
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// sides an instance can be installed as
+const (
+	SideClient = "client"
+	SideServer = "server"
+)
+
 type Entry struct {
-	Slug          string `json:"slug"`
-	Version       string `json:"version"`
-	VersionNumber string `json:"version_number"` // human-readable
-	Dest          string `json:"dest"`
-	Checksum      string `json:"sha1"`
-	Filename      string `json:"filename"` // file name in the archive
-	Enable        bool   `json:"enable"`
+	Slug          string   `json:"slug"`
+	Version       string   `json:"version"`
+	VersionNumber string   `json:"version_number"` // human-readable
+	Dest          string   `json:"dest"`
+	Checksum      string   `json:"sha1"`
+	SHA512        string   `json:"sha512,omitempty"`
+	Filename      string   `json:"filename"` // file name in the archive
+	Enable        bool     `json:"enable"`
+	ClientSide    string   `json:"client_side,omitempty"` // "required" | "optional" | "unsupported"
+	ServerSide    string   `json:"server_side,omitempty"` // "required" | "optional" | "unsupported"
+	Worlds        []string `json:"worlds,omitempty"`      // per-world types only, empty = every world
+	ProjectID     string   `json:"project_id,omitempty"`  // provider project id (Modrinth base62, CurseForge numeric)
+	Source        string   `json:"source,omitempty"`      // "" (modrinth), "curseforge", "url", "file", "github", "maven"
+	URL           string   `json:"url,omitempty"`         // url source: https download
+	Path          string   `json:"path,omitempty"`        // file source: relative to the manifest
+	Repo          string   `json:"repo,omitempty"`        // github source: owner/name
+	Tag           string   `json:"tag,omitempty"`         // github source: release tag, empty = latest
+	Asset         string   `json:"asset,omitempty"`       // github source: asset name glob
+	Maven         string   `json:"maven,omitempty"`       // maven source: group:artifact[:version[:classifier]][@ext]
+	Repository    string   `json:"repository,omitempty"`  // maven source: repository root url
+	Modpack       bool     `json:"modpack,omitempty"`     // managed by the modpack, pinned to the version it ships
+	Pinned        bool     `json:"pinned,omitempty"`      // keep Version instead of resolving the newest one
 }
 
 type Minecraft struct {
@@ -16,12 +42,66 @@ type Minecraft struct {
 	Version       string `json:"minecraft_version"` // minecraft 1.21.6
 }
 
+// Modpack records the pack a manifest was created from, so it can be updated.
+type Modpack struct {
+	Name          string `json:"name"`
+	Slug          string `json:"slug,omitempty"`    // Modrinth project, empty for a local .mrpack
+	Version       string `json:"version,omitempty"` // Modrinth version id
+	VersionNumber string `json:"version_number"`
+}
+
+// Manifest is saved as a flat JSON object: the header fields below plus one
+// array per content type section ("mods", "resourcepacks", "shaders", ...).
 type Manifest struct {
-	Schema        int       `json:"schema"` // modrinth-cli ver
-	Minecraft     Minecraft `json:"minecraft"`
-	Mods          []Entry   `json:"mods"`
-	ResourcePacks []Entry   `json:"resourcepacks"`
-	Shaders       []Entry   `json:"shaders"`
-	path          string    // absolute
-	baseDir       string    // absolute
+	Schema    int                `json:"schema"` // modrinth-cli ver
+	Minecraft Minecraft          `json:"minecraft"`
+	Side      string             `json:"side,omitempty"` // client (default) or server
+	Modpack   *Modpack           `json:"modpack,omitempty"`
+	sections  map[string][]Entry // keyed by ContentType.Section
+	path      string             // absolute
+	baseDir   string             // absolute
+	hashes    *checksum.Index    // file hashes under baseDir, nil if not loaded from disk
+}
+
+// @brief SourceName returns where the entry comes from.
+// @return Entry.Source, or "modrinth" when it is empty
+func (e Entry) SourceName() string {
+	if e.Source == "" {
+		return source.Modrinth
+	}
+	return e.Source
+}
+
+// @brief Sums returns the hashes recorded for the entry's file.
+// Manifests written before SHA-512 was recorded only have the SHA1.
+func (e Entry) Sums() checksum.Sums {
+	return checksum.Sums{SHA1: e.Checksum, SHA512: e.SHA512}
+}
+
+// @brief SupportsSide reports whether the entry may be installed on a side.
+// Entries without recorded side data are assumed to work everywhere.
+// @param side SideClient or SideServer
+// @return false only if the project marks that side as unsupported
+func (e Entry) SupportsSide(side string) bool {
+	switch side {
+	case SideServer:
+		return e.ServerSide != "unsupported"
+	default:
+		return e.ClientSide != "unsupported"
+	}
+}
+
+// @brief SideLabel is a short description of where the entry runs, for listings.
+// @return "client", "server", "both" or "-" when unknown
+func (e Entry) SideLabel() string {
+	switch {
+	case e.ClientSide == "" && e.ServerSide == "":
+		return "-"
+	case e.ServerSide == "unsupported":
+		return SideClient
+	case e.ClientSide == "unsupported":
+		return SideServer
+	default:
+		return "both"
+	}
 }
@@ -0,0 +1,75 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// @brief FindWorlds lists the worlds under a game dir.
+// A world is any folder holding a level.dat, either directly in the game dir
+// (dedicated servers) or under saves/ (clients).
+// @param gameDir path to the game directory
+// @return world paths relative to gameDir, sorted
+func FindWorlds(gameDir string) []string {
+	var worlds []string
+	for _, parent := range []string{"", "saves"} {
+		entries, err := os.ReadDir(filepath.Join(gameDir, parent))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			rel := filepath.Join(parent, e.Name())
+			if isWorld(filepath.Join(gameDir, rel)) {
+				worlds = append(worlds, rel)
+			}
+		}
+	}
+	sort.Strings(worlds)
+	return worlds
+}
+
+// @brief ResolveWorld turns a world name into a path relative to the game dir.
+// "world" stays "world" on a server; "My World" becomes "saves/My World" on a client.
+// @param gameDir path to the game directory
+// @param name world folder name or relative path
+// @return relative world path
+func ResolveWorld(gameDir, name string) string {
+	if isWorld(filepath.Join(gameDir, name)) {
+		return name
+	}
+	if saves := filepath.Join("saves", name); isWorld(filepath.Join(gameDir, saves)) {
+		return saves
+	}
+	return name
+}
+
+func isWorld(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "level.dat"))
+	return err == nil
+}
+
+// @brief Dirs lists the folders an entry's file lives in.
+// Per-world types get one folder per targeted world, or per world found
+// when the entry names none.
+// @param gameDir path to the game directory
+// @param ct content type of the entry
+// @param e manifest entry
+// @return absolute or gameDir-relative folders (empty if no world exists yet)
+func Dirs(gameDir string, ct ContentType, e Entry) []string {
+	if !ct.PerWorld {
+		return []string{filepath.Join(gameDir, e.Dest)}
+	}
+	worlds := e.Worlds
+	if len(worlds) == 0 {
+		worlds = FindWorlds(gameDir)
+	}
+	dirs := make([]string, 0, len(worlds))
+	for _, w := range worlds {
+		dirs = append(dirs, filepath.Join(gameDir, ResolveWorld(gameDir, w), e.Dest))
+	}
+	return dirs
+}
@@ -0,0 +1,148 @@
+package modlist
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// Formats are the built-in templates, by --format name.
+var Formats = []string{"md", "html", "csv"}
+
+// List is the data a template renders.
+type List struct {
+	Name      string
+	Minecraft manifest.Minecraft
+	Groups    []Group
+}
+
+// Group holds the enabled entries of one content type.
+type Group struct {
+	Title   string // "Mods", "Resource packs"
+	Section string // manifest section ("mods")
+	Items   []Item
+}
+
+// Item is one entry with its project page.
+type Item struct {
+	Slug        string
+	Title       string
+	Author      string
+	Version     string // version number
+	Side        string // "client", "server", "both" or "-"
+	License     string
+	URL         string
+	Description string
+}
+
+// @brief Build collects the enabled entries of a manifest, grouped by content type.
+// @param m manifest
+// @param name pack name for the heading
+// @param prjs project pages keyed by slug (see Installer.Projects)
+// @return list ready to render
+func Build(m *manifest.Manifest, name string, prjs map[string]source.Project) *List {
+	l := &List{Name: name, Minecraft: m.Minecraft}
+	for _, ct := range manifest.ContentTypes() {
+		g := Group{Section: ct.Section, Title: title(ct.Label)}
+		for _, e := range m.Entries(ct) {
+			if !e.Enable {
+				continue
+			}
+			p := prjs[e.Slug]
+			it := Item{
+				Slug:        e.Slug,
+				Title:       p.Title,
+				Author:      p.Author,
+				Version:     e.VersionNumber,
+				Side:        e.SideLabel(),
+				License:     p.License,
+				URL:         p.URL,
+				Description: p.Description,
+			}
+			if it.Title == "" {
+				it.Title = e.Slug
+			}
+			g.Items = append(g.Items, it)
+		}
+		if len(g.Items) > 0 {
+			l.Groups = append(l.Groups, g)
+		}
+	}
+	return l
+}
+
+// @brief Render writes a list through a built-in or user template.
+// @param w destination
+// @param format built-in template name, ignored when tmplPath is set
+// @param tmplPath user template file, empty for the built-in one
+// @param l data to render
+// @return error if the template is unknown, invalid or fails
+func Render(w io.Writer, format, tmplPath string, l *List) error {
+	var text []byte
+	var err error
+	if tmplPath != "" {
+		text, err = os.ReadFile(tmplPath)
+	} else {
+		text, err = builtin.ReadFile("templates/" + format + ".tmpl")
+		if err != nil {
+			return fmt.Errorf("unknown format %q, use one of %s or --template", format, strings.Join(Formats, ", "))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	t, err := template.New("list").Funcs(funcs).Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("template: %w", err)
+	}
+	return t.Execute(w, l)
+}
+
+// @brief Template returns the text of a built-in template, as a starting point for your own.
+// @param format built-in template name
+// @return template text or error if there is none
+func Template(format string) (string, error) {
+	b, err := builtin.ReadFile("templates/" + format + ".tmpl")
+	if err != nil {
+		return "", fmt.Errorf("unknown format %q, use one of %s", format, strings.Join(Formats, ", "))
+	}
+	return string(b), nil
+}
+
+// template helpers next to the built-in html, js and urlquery
+var funcs = template.FuncMap{
+	// md escapes table cell text
+	"md": func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.Join(strings.Fields(s), " ")
+	},
+	// csv quotes fields and joins them into one record
+	"csv": func(fields ...string) (string, error) {
+		var sb strings.Builder
+		cw := csv.NewWriter(&sb)
+		if err := cw.Write(fields); err != nil {
+			return "", err
+		}
+		cw.Flush()
+		return strings.TrimSuffix(sb.String(), "\n"), cw.Error()
+	},
+}
+
+// title turns "RESOURCE PACKS" into "Resource packs".
+func title(label string) string {
+	s := strings.ToLower(label)
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
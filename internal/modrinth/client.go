@@ -64,6 +64,9 @@ func (c *Client) doJSON(
 	if err != nil {
 		return err
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := c.http.Do(req)
 	if err != nil {
@@ -121,4 +124,22 @@ func (c *Client) GetProject(ctx context.Context, p ProjectQuery) (*Project, erro
 	return getJSON[Project](ctx, c, path, nil)
 }
 
+// @brief GET /projects?ids=[...]
+// @param ids project ids or slugs
+// @return the projects that exist, in no particular order
+func (c *Client) GetProjects(ctx context.Context, ids []string) ([]Project, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	out, err := getJSON[[]Project](ctx, c, "projects", url.Values{"ids": {string(b)}})
+	if err != nil {
+		return nil, err
+	}
+	return *out, nil
+}
+
 //
@@ -19,6 +19,7 @@ type Project struct {
 	Follows              int      `json:"follows"`               // 24 330
 	IconURL              string   `json:"icon_url"`              // 96×96 icon
 	LatestVersion        string   `json:"latest_version"`        // "oZOSEhyy"
+	Loaders              []string `json:"loaders"`               // ["fabric", "datapack"], project endpoint only
 	License              License  `json:"license"`               // "LicenseRef-Polyform-Shield-1.0.0"
 	ProjectID            string   `json:"project_id"`            // "AANobbMI"
 	ProjectType          string   `json:"project_type"`          // "mod", "modpack", etc.
@@ -0,0 +1,167 @@
+package modrinth
+
+import (
+	"context"
+
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// Client is the Modrinth source.Provider.
+var _ source.Provider = (*Client)(nil)
+
+// @brief Name identifies Modrinth entries.
+func (c *Client) Name() string {
+	return source.Modrinth
+}
+
+// @brief SearchProjects runs a search with version and project type facets.
+// @param ctx context for cancellation
+// @param query search text
+// @param f filter; Loader is left to the caller since hits mix loaders into categories
+// @param limit max results, 0 for the API default
+// @return matching projects or error
+func (c *Client) SearchProjects(ctx context.Context, query string, f source.Filter, limit int) ([]source.Project, error) {
+	res, err := c.Search(ctx, SearchParams{
+		Query: query,
+		Facets: Facets{
+			ProjectType:      f.ProjectTypes,
+			MinecraftVersion: f.GameVersion,
+		},
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]source.Project, 0, len(res.Hits))
+	for i := range res.Hits {
+		out = append(out, res.Hits[i].toSource())
+	}
+	return out, nil
+}
+
+// @brief LookupProject fetches a project by id or slug.
+// @param ctx context for cancellation
+// @param id project id or slug
+// @return project or error
+func (c *Client) LookupProject(ctx context.Context, id string) (*source.Project, error) {
+	prj, err := c.GetProject(ctx, ProjectQuery{Slug: id})
+	if err != nil {
+		return nil, err
+	}
+	p := prj.toSource()
+	return &p, nil
+}
+
+// @brief ListVersions lists versions for a game version and loader, newest first.
+// @param ctx context for cancellation
+// @param projectID project id or slug
+// @param f filter
+// @return versions or error
+func (c *Client) ListVersions(ctx context.Context, projectID string, f source.Filter) ([]source.Version, error) {
+	vers, err := c.ProjectVersions(ctx, projectID, f.GameVersion, f.Loader)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]source.Version, 0, len(vers))
+	for _, v := range vers {
+		out = append(out, source.Version{
+			ID:           v.ID,
+			Number:       v.VersionNumber,
+			Published:    v.DatePublished,
+			GameVersions: v.GameVersions,
+			Loaders:      v.Loaders,
+		})
+	}
+	return out, nil
+}
+
+// @brief ResolveFiles lists a version's files, primary file first.
+// @param ctx context for cancellation
+// @param projectID unused, version ids are global on Modrinth
+// @param versionID version id
+// @return releases or error
+func (c *Client) ResolveFiles(ctx context.Context, projectID, versionID string) ([]source.Release, error) {
+	v, err := c.Version(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]source.Release, 0, len(v.Files))
+	for _, f := range v.Files {
+		rel := source.Release{
+			Version:       v.ID,
+			VersionNumber: v.VersionNumber,
+			Filename:      f.Filename,
+			URL:           f.URL,
+			SHA1:          f.Hashes.SHA1,
+			SHA512:        f.Hashes.SHA512,
+			Size:          f.Size,
+		}
+		if f.Primary {
+			out = append([]source.Release{rel}, out...)
+		} else {
+			out = append(out, rel)
+		}
+	}
+	return out, nil
+}
+
+// @brief VerifyFile checks a file against the SHA1 Modrinth publishes.
+func (c *Client) VerifyFile(path string, rel *source.Release) error {
+	return source.VerifyHashes(path, rel)
+}
+
+// @brief toSource converts a project or search hit to the provider-neutral form.
+func (p *Project) toSource() source.Project {
+	id := p.Id
+	if id == "" {
+		id = p.ProjectID // search hits
+	}
+	loaders := p.Loaders
+	if len(loaders) == 0 {
+		loaders = p.Categories // search hits mix loaders into categories
+	}
+	return source.Project{
+		ID:           id,
+		Slug:         p.Slug,
+		Title:        p.Title,
+		Author:       p.Author,
+		Description:  p.Description,
+		ProjectType:  p.ProjectType,
+		Loaders:      loaders,
+		GameVersions: p.Versions,
+		ClientSide:   p.ClientSide,
+		ServerSide:   p.ServerSide,
+		License:      p.License.Id,
+		LicenseName:  p.License.Name,
+		URL:          "https://modrinth.com/" + p.ProjectType + "/" + p.Slug,
+	}
+}
+
+// @brief LookupProjects fetches several projects at once, with their owners as Author.
+// @param ctx context for cancellation
+// @param ids project ids or slugs
+// @return the projects that exist, in no particular order
+func (c *Client) LookupProjects(ctx context.Context, ids []string) ([]source.Project, error) {
+	prjs, err := c.GetProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]string, 0, len(prjs))
+	for _, p := range prjs {
+		if p.Team != "" {
+			teams = append(teams, p.Team)
+		}
+	}
+	owners, err := c.TeamOwners(ctx, teams)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]source.Project, 0, len(prjs))
+	for i := range prjs {
+		if prjs[i].Author == "" {
+			prjs[i].Author = owners[prjs[i].Team]
+		}
+		out = append(out, prjs[i].toSource())
+	}
+	return out, nil
+}
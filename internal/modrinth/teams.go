@@ -0,0 +1,43 @@
+package modrinth
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// TeamMember is one member of a project's team.
+type TeamMember struct {
+	TeamID string `json:"team_id"`
+	User   struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Role     string `json:"role"` // "Owner", "Member", ...
+	Ordering int    `json:"ordering"`
+}
+
+// @brief GET /teams?ids=[...]
+// @param ids team ids (Project.Team)
+// @return username of each team's owner (or first member), keyed by team id
+func (c *Client) TeamOwners(ctx context.Context, ids []string) (map[string]string, error) {
+	out := map[string]string{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	b, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := getJSON[[][]TeamMember](ctx, c, "teams", url.Values{"ids": {string(b)}})
+	if err != nil {
+		return nil, err
+	}
+	for _, members := range *teams {
+		for _, m := range members {
+			if _, ok := out[m.TeamID]; !ok || m.Role == "Owner" {
+				out[m.TeamID] = m.User.Username
+			}
+		}
+	}
+	return out, nil
+}
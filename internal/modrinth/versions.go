@@ -2,8 +2,11 @@
 package modrinth
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
 )
@@ -14,7 +17,8 @@ import (
 
 // Hashes holds file checksums.
 type Hashes struct {
-	SHA1 string `json:"sha1"`
+	SHA1   string `json:"sha1"`
+	SHA512 string `json:"sha512"`
 }
 
 // File is one downloadable binary / resource-pack / shader-pack.
@@ -23,14 +27,18 @@ type File struct {
 	URL      string `json:"url"`
 	Primary  bool   `json:"primary"`
 	Hashes   Hashes `json:"hashes"`
+	Size     int64  `json:"size"`
 }
 
 // Version represents an element of /project/{slug}/version
 type Version struct {
-	ID            string `json:"id"`
-	VersionNumber string `json:"version_number"`
-	DatePublished string `json:"date_published"`
-	Files         []File `json:"files"`
+	ID            string   `json:"id"`
+	ProjectID     string   `json:"project_id"`
+	VersionNumber string   `json:"version_number"`
+	DatePublished string   `json:"date_published"`
+	GameVersions  []string `json:"game_versions"`
+	Loaders       []string `json:"loaders"`
+	Files         []File   `json:"files"`
 }
 
 // ────────────────────────────────────────────────────────────────
@@ -83,10 +91,30 @@ func ParseSlug(s string) string {
 	if !regexp.MustCompile(`/`).MatchString(s) {
 		return s
 	}
-	re := regexp.MustCompile(`modrinth\.com/(?:mod|plugin|datapack|shaderpack|resourcepack)/([^/?#]+)`)
+	re := regexp.MustCompile(`modrinth\.com/(?:mod|modpack|plugin|datapack|shader|shaderpack|resourcepack)/([^/?#]+)`)
 	m := re.FindStringSubmatch(s)
 	if len(m) > 1 {
 		return m[1]
 	}
 	return s // fallback – let the API 404 if it’s invalid
 }
+
+// @brief VersionsByHash looks up the versions that published files with the given hashes.
+// @param ctx context for cancellation
+// @param algorithm "sha1" or "sha512"
+// @param hashes file hashes
+// @return versions keyed by hash; unknown hashes are left out
+func (c *Client) VersionsByHash(ctx context.Context, algorithm string, hashes []string) (map[string]Version, error) {
+	out := map[string]Version{}
+	if len(hashes) == 0 {
+		return out, nil
+	}
+	body, err := json.Marshal(map[string]any{"hashes": hashes, "algorithm": algorithm})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "version_files", nil, bytes.NewReader(body), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,248 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/fsutil"
+)
+
+// IndexFile is the metadata file at the root of every .mrpack.
+const IndexFile = "modrinth.index.json"
+
+// Index is modrinth.index.json, format version 1.
+type Index struct {
+	FormatVersion int               `json:"formatVersion"` // always 1
+	Game          string            `json:"game"`          // always "minecraft"
+	VersionID     string            `json:"versionId"`
+	Name          string            `json:"name"`
+	Summary       string            `json:"summary,omitempty"`
+	Files         []File            `json:"files"`
+	Dependencies  map[string]string `json:"dependencies"` // "minecraft", "fabric-loader", ...
+}
+
+// File is one download listed in the index.
+type File struct {
+	Path      string   `json:"path"` // relative to the instance, forward slashes
+	Hashes    Hashes   `json:"hashes"`
+	Env       *Env     `json:"env,omitempty"` // nil = required on both sides
+	Downloads []string `json:"downloads"`
+	FileSize  int64    `json:"fileSize"`
+}
+
+// Hashes of a listed file; both are required by the format.
+type Hashes struct {
+	SHA1   string `json:"sha1"`
+	SHA512 string `json:"sha512"`
+}
+
+// Env says whether a file is "required", "optional" or "unsupported" on each side.
+type Env struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+}
+
+// dependency keys for the loaders the format knows
+var loaderKeys = map[string]string{
+	"fabric":   "fabric-loader",
+	"quilt":    "quilt-loader",
+	"forge":    "forge",
+	"neoforge": "neoforge",
+}
+
+// hosts launchers accept in Downloads; anything else has to be embedded
+var allowedHosts = map[string]bool{
+	"cdn.modrinth.com":          true,
+	"github.com":                true,
+	"raw.githubusercontent.com": true,
+	"gitlab.com":                true,
+}
+
+// @brief LoaderKey maps a manifest loader to its dependency key in the index.
+// @param loader e.g. "fabric"
+// @return key ("fabric-loader") and true, or false if the format has no such loader
+func LoaderKey(loader string) (string, bool) {
+	k, ok := loaderKeys[loader]
+	return k, ok
+}
+
+// @brief LoaderFromKey maps a dependency key back to a manifest loader.
+// @param key e.g. "fabric-loader"
+// @return loader ("fabric") and true, or false if key is not a loader
+func LoaderFromKey(key string) (string, bool) {
+	for l, k := range loaderKeys {
+		if k == key {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// @brief AllowedDownload reports whether launchers will fetch a url listed in an index.
+// @param raw download url
+// @return true for https urls on the hosts the format allows
+func AllowedDownload(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && allowedHosts[u.Host]
+}
+
+// Pack is everything that goes into a .mrpack archive.
+type Pack struct {
+	Index     Index
+	Overrides map[string]string // archive folder ("overrides", "client-overrides", ...) -> folder on disk
+	Embedded  map[string]string // path under overrides/ -> file on disk, for content that cannot be linked
+}
+
+// @brief Write creates the .mrpack archive.
+// Embedded files win over an override file with the same path.
+// @param dest output path
+// @return error if a file could not be read or written
+func (p *Pack) Write(dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(out)
+
+	if err := p.write(zw); err != nil {
+		zw.Close()
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func (p *Pack) write(zw *zip.Writer) error {
+	w, err := zw.Create(IndexFile)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(p.Index); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for rel, src := range p.Embedded {
+		name := path.Join("overrides", filepath.ToSlash(rel))
+		if err := addFile(zw, name, src); err != nil {
+			return err
+		}
+		seen[name] = true
+	}
+
+	for folder, dir := range p.Overrides {
+		err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			name := path.Join(folder, filepath.ToSlash(rel))
+			if seen[name] {
+				return nil
+			}
+			return addFile(zw, name, file)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addFile(zw *zip.Writer, name, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("mrpack: %w", err)
+	}
+	defer in.Close()
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, in)
+	return err
+}
+
+// Archive is an opened .mrpack.
+type Archive struct {
+	Index Index
+	zr    *zip.ReadCloser
+}
+
+// @brief Open reads a .mrpack and its index.
+// @param src path to the archive
+// @return archive, to be closed by the caller, or error
+func Open(src string) (*Archive, error) {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, err
+	}
+	a := &Archive{zr: zr}
+	f, err := zr.Open(IndexFile)
+	if err != nil {
+		zr.Close()
+		return nil, fmt.Errorf("%s: no %s, not a Modrinth modpack", src, IndexFile)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&a.Index); err != nil {
+		zr.Close()
+		return nil, fmt.Errorf("%s: %w", IndexFile, err)
+	}
+	if a.Index.Game != "minecraft" {
+		zr.Close()
+		return nil, fmt.Errorf("%s: unsupported game %q", src, a.Index.Game)
+	}
+	for _, f := range a.Index.Files {
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			zr.Close()
+			return nil, fmt.Errorf("%s: refusing file %q outside the game dir", IndexFile, f.Path)
+		}
+	}
+	return a, nil
+}
+
+// @brief Close releases the archive.
+func (a *Archive) Close() error {
+	return a.zr.Close()
+}
+
+// @brief Extract copies everything under one archive folder to disk, overwriting.
+// @param folder archive folder ("overrides", "client-overrides", ...)
+// @param dest folder to extract into
+// @return extracted paths relative to dest, or error
+func (a *Archive) Extract(folder, dest string) ([]string, error) {
+	var out []string
+	prefix := folder + "/"
+	for _, f := range a.zr.File {
+		if !strings.HasPrefix(f.Name, prefix) || f.FileInfo().IsDir() {
+			continue
+		}
+		rel := strings.TrimPrefix(f.Name, prefix)
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return out, fmt.Errorf("refusing to extract %q outside %s", f.Name, dest)
+		}
+		if err := fsutil.ExtractFile(f, filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+			return out, err
+		}
+		out = append(out, rel)
+	}
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteOpen(t *testing.T) {
+	tmp := t.TempDir()
+	write := func(rel, body string) string {
+		p := filepath.Join(tmp, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	write("overrides/config/a.txt", "override")
+	write("overrides/mods/local.jar", "from overrides")
+	write("client/options.txt", "client")
+	embedded := write("embedded.jar", "embedded")
+
+	idx := Index{
+		FormatVersion: 1,
+		Game:          "minecraft",
+		VersionID:     "1.0",
+		Name:          "Test",
+		Files: []File{{
+			Path:      "mods/sodium.jar",
+			Hashes:    Hashes{SHA1: "aa", SHA512: "bb"},
+			Env:       &Env{Client: "required", Server: "unsupported"},
+			Downloads: []string{"https://cdn.modrinth.com/data/x/sodium.jar"},
+			FileSize:  42,
+		}},
+		Dependencies: map[string]string{"minecraft": "1.20.1", "fabric-loader": "0.15.7"},
+	}
+	p := &Pack{
+		Index:     idx,
+		Overrides: map[string]string{"overrides": filepath.Join(tmp, "overrides"), "client-overrides": filepath.Join(tmp, "client")},
+		Embedded:  map[string]string{"mods/local.jar": embedded},
+	}
+	dest := filepath.Join(tmp, "pack.mrpack")
+	if err := p.Write(dest); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	if !reflect.DeepEqual(a.Index, idx) {
+		t.Errorf("index = %+v, want %+v", a.Index, idx)
+	}
+
+	out := t.TempDir()
+	got, err := a.Extract("overrides", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("extracted %v, want config/a.txt and mods/local.jar once each", got)
+	}
+	for rel, want := range map[string]string{"config/a.txt": "override", "mods/local.jar": "embedded"} {
+		b, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(rel)))
+		if err != nil || string(b) != want {
+			t.Errorf("%s = %q, %v; want %q", rel, b, err, want)
+		}
+	}
+	if got, err := a.Extract("client-overrides", out); err != nil || len(got) != 1 || got[0] != "options.txt" {
+		t.Errorf("client-overrides = %v, %v", got, err)
+	}
+}
+
+func TestExtractOutside(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "evil.mrpack")
+	f, err := os.Create(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create(IndexFile)
+	w.Write([]byte(`{"formatVersion":1,"game":"minecraft","files":[]}`))
+	w, _ = zw.Create("overrides/../../escape.txt")
+	w.Write([]byte("x"))
+	zw.Close()
+	f.Close()
+
+	a, err := Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	if _, err := a.Extract("overrides", t.TempDir()); err == nil {
+		t.Error("Extract wrote a file outside the destination")
+	}
+}
+
+func TestOpenFileOutside(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "mod", path: "mods/sodium.jar"},
+		{name: "parent", path: "../evil.jar", wantErr: true},
+		{name: "nested parent", path: "mods/../../evil.jar", wantErr: true},
+		{name: "absolute", path: "/tmp/evil.jar", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "pack.mrpack")
+			f, err := os.Create(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			zw := zip.NewWriter(f)
+			w, _ := zw.Create(IndexFile)
+			fmt.Fprintf(w, `{"formatVersion":1,"game":"minecraft","files":[{"path":%q,"hashes":{"sha1":"aa"},"downloads":["https://cdn.modrinth.com/x.jar"]}]}`, tt.path)
+			zw.Close()
+			f.Close()
+
+			a, err := Open(dest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				a.Close()
+			}
+		})
+	}
+}
+
+func TestAllowedDownload(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://cdn.modrinth.com/data/x/y.jar", true},
+		{"https://github.com/o/r/releases/download/v1/y.jar", true},
+		{"http://cdn.modrinth.com/data/x/y.jar", false},
+		{"https://edge.forgecdn.net/files/1/2/y.jar", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		if got := AllowedDownload(tt.url); got != tt.want {
+			t.Errorf("AllowedDownload(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
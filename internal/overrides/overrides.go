@@ -0,0 +1,246 @@
+package overrides
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/fsutil"
+)
+
+// folder names next to project.json; the side folder is layered over the common one
+const (
+	Common = "overrides"
+	Client = "client-overrides"
+	Server = "server-overrides"
+)
+
+// StateDir holds per-instance bookkeeping inside the game dir.
+const StateDir = ".modcli"
+
+const stateFile = "overrides.json"
+
+// state remembers what we placed, so edits made afterwards are recognised.
+type state struct {
+	Files map[string]string `json:"files"` // path relative to the game dir -> sha1 we wrote
+}
+
+// Result lists what Apply did, by path relative to the game dir.
+type Result struct {
+	Placed    []string // new or updated from the pack
+	Unchanged []string // already identical
+	Conflicts []string // edited locally (or never ours), left alone
+}
+
+// @brief Dirs returns the override folders that apply to a side, lowest priority first.
+// @param packDir folder holding project.json
+// @param side "client" or "server"
+// @return existing folders among overrides/ and <side>-overrides/
+func Dirs(packDir, side string) []string {
+	layers := []string{Common, Client}
+	if side == "server" {
+		layers[1] = Server
+	}
+	var dirs []string
+	for _, l := range layers {
+		d := filepath.Join(packDir, l)
+		if fi, err := os.Stat(d); err == nil && fi.IsDir() {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// @brief Apply copies override files into the game dir without clobbering local edits.
+// A file is overwritten only if it is missing or still holds exactly what we placed
+// last time; anything else is reported as a conflict unless force is set.
+// @param dirs override folders, lowest priority first (see Dirs)
+// @param gameDir path to the game directory
+// @param force overwrite conflicting files too
+// @return what happened to each file, or error
+func Apply(dirs []string, gameDir string, force bool) (*Result, error) {
+	st, err := loadState(gameDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// later layers win
+	files := map[string]string{} // rel -> source path
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)] = path
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	res := &Result{}
+	for _, rel := range rels {
+		src := files[rel]
+		dst := filepath.Join(gameDir, filepath.FromSlash(rel))
+
+		want, err := checksum.FileWith(src, checksum.SHA1)
+		if err != nil {
+			return nil, err
+		}
+		have, err := checksum.FileWith(dst, checksum.SHA1)
+		switch {
+		case os.IsNotExist(err):
+			// nothing there yet
+		case err != nil:
+			return nil, err
+		case have == want:
+			st.Files[rel] = want
+			res.Unchanged = append(res.Unchanged, rel)
+			continue
+		case have != st.Files[rel] && !force:
+			res.Conflicts = append(res.Conflicts, rel)
+			continue
+		}
+
+		if err := fsutil.CopyFile(src, dst); err != nil {
+			return nil, fmt.Errorf("override %s: %w", rel, err)
+		}
+		st.Files[rel] = want
+		res.Placed = append(res.Placed, rel)
+	}
+	return res, saveState(gameDir, st)
+}
+
+// @brief Capture copies a live file or folder from the game dir into an override folder.
+// Captured files are recorded as placed, so the next Apply sees them as unchanged.
+// @param dir override folder to capture into
+// @param gameDir path to the game directory
+// @param path file or folder, relative to the game dir
+// @return captured paths relative to the game dir, or error
+func Capture(dir, gameDir, path string) ([]string, error) {
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(gameDir, path)
+		if err != nil {
+			return nil, err
+		}
+		path = rel
+	}
+	if path == "." || !filepath.IsLocal(path) {
+		return nil, fmt.Errorf("%s is not inside the game dir", path)
+	}
+
+	st, err := loadState(gameDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var captured []string
+	err = filepath.WalkDir(filepath.Join(gameDir, path), func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(gameDir, p)
+		if err != nil {
+			return err
+		}
+		if err := fsutil.CopyFile(p, filepath.Join(dir, rel)); err != nil {
+			return err
+		}
+		sum, err := checksum.FileWith(p, checksum.SHA1)
+		if err != nil {
+			return err
+		}
+		st.Files[filepath.ToSlash(rel)] = sum
+		captured = append(captured, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return captured, saveState(gameDir, st)
+}
+
+// @brief Placed lists the files Apply or Capture put into the game dir.
+// @param gameDir path to the game directory
+// @return slash paths relative to gameDir, or error if the state is unreadable
+func Placed(gameDir string) (map[string]bool, error) {
+	st, err := loadState(gameDir)
+	if err != nil {
+		return nil, err
+	}
+	placed := make(map[string]bool, len(st.Files))
+	for rel := range st.Files {
+		placed[rel] = true
+	}
+	return placed, nil
+}
+
+// @brief Copy duplicates every override folder of a pack into another pack folder.
+// @param packDir folder holding project.json
+// @param dest folder to copy overrides/, client-overrides/ and server-overrides/ into
+// @return error if a file could not be copied
+func Copy(packDir, dest string) error {
+	for _, l := range []string{Common, Client, Server} {
+		src := filepath.Join(packDir, l)
+		if fi, err := os.Stat(src); err != nil || !fi.IsDir() {
+			continue
+		}
+		err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(packDir, p)
+			if err != nil {
+				return err
+			}
+			return fsutil.CopyFile(p, filepath.Join(dest, rel))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadState(gameDir string) (*state, error) {
+	st := &state{Files: map[string]string{}}
+	b, err := os.ReadFile(filepath.Join(gameDir, StateDir, stateFile))
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, st); err != nil {
+		return nil, fmt.Errorf("override state: %w", err)
+	}
+	if st.Files == nil {
+		st.Files = map[string]string{}
+	}
+	return st, nil
+}
+
+func saveState(gameDir string, st *state) error {
+	dir := filepath.Join(gameDir, StateDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, _ := json.MarshalIndent(st, "", " ")
+	return os.WriteFile(filepath.Join(dir, stateFile), b, 0o644)
+}
@@ -0,0 +1,374 @@
+package packwiz
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+	"github.com/silask7188/ModrinthCLI/internal/fsutil"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+// file names and the format version written on export
+const (
+	PackFile   = "pack.toml"
+	IndexFile  = "index.toml"
+	PackFormat = "packwiz:1.1.0"
+	metaSuffix = ".pw.toml"
+)
+
+// PackMeta is pack.toml.
+type PackMeta struct {
+	Name       string            `toml:"name"`
+	Author     string            `toml:"author,omitempty"`
+	Version    string            `toml:"version,omitempty"`
+	PackFormat string            `toml:"pack-format"`
+	Index      IndexRef          `toml:"index"`
+	Versions   map[string]string `toml:"versions"` // "minecraft", "fabric", "forge", ...
+}
+
+// IndexRef points pack.toml at index.toml.
+type IndexRef struct {
+	File       string `toml:"file"`
+	HashFormat string `toml:"hash-format"`
+	Hash       string `toml:"hash"`
+}
+
+// Index is index.toml.
+type Index struct {
+	HashFormat string       `toml:"hash-format"`
+	Files      []IndexEntry `toml:"files"`
+}
+
+// IndexEntry is one file listed in index.toml.
+type IndexEntry struct {
+	File       string `toml:"file"` // slash path relative to the pack
+	Hash       string `toml:"hash"`
+	HashFormat string `toml:"hash-format,omitempty"` // overrides the index's
+	Metafile   bool   `toml:"metafile,omitempty"`
+}
+
+// Metafile is a .pw.toml describing one downloadable file.
+type Metafile struct {
+	Name     string   `toml:"name"`
+	Filename string   `toml:"filename"`
+	Side     string   `toml:"side,omitempty"` // "both" | "client" | "server"
+	Download Download `toml:"download"`
+	Option   *Option  `toml:"option,omitempty"`
+	Update   *Update  `toml:"update,omitempty"`
+}
+
+// Download says where a metafile's file comes from.
+type Download struct {
+	URL        string `toml:"url,omitempty"`
+	HashFormat string `toml:"hash-format"`
+	Hash       string `toml:"hash"`
+	Mode       string `toml:"mode,omitempty"` // "metadata:curseforge" when the url may not be listed
+}
+
+// Option marks a file the user may turn off.
+type Option struct {
+	Optional    bool   `toml:"optional"`
+	Default     bool   `toml:"default"`
+	Description string `toml:"description,omitempty"`
+}
+
+// Update pins a metafile to a provider version.
+type Update struct {
+	Modrinth   *ModrinthUpdate   `toml:"modrinth,omitempty"`
+	CurseForge *CurseForgeUpdate `toml:"curseforge,omitempty"`
+}
+
+// ModrinthUpdate is [update.modrinth].
+type ModrinthUpdate struct {
+	ModID   string `toml:"mod-id"`
+	Version string `toml:"version"`
+}
+
+// CurseForgeUpdate is [update.curseforge].
+type CurseForgeUpdate struct {
+	FileID    int `toml:"file-id"`
+	ProjectID int `toml:"project-id"`
+}
+
+// Pack is a packwiz pack in memory.
+type Pack struct {
+	Meta      PackMeta
+	Metafiles map[string]Metafile // slash path relative to the pack ("mods/sodium.pw.toml")
+	Files     map[string]string   // plain files: slash path relative to the pack -> file on disk
+}
+
+// loader names as packwiz spells them in [versions]
+var loaderKeys = map[string]string{
+	"fabric":     "fabric",
+	"quilt":      "quilt",
+	"forge":      "forge",
+	"neoforge":   "neoforge",
+	"liteloader": "liteloader",
+}
+
+/*
+--------------------------------------------------
+  READ
+--------------------------------------------------
+*/
+
+// @brief Read loads pack.toml, index.toml and every file the index lists,
+// checking each against its index hash.
+// @param dir pack folder
+// @return pack or error if a file is missing, malformed or does not match its hash
+func Read(dir string) (*Pack, error) {
+	p := &Pack{Metafiles: map[string]Metafile{}, Files: map[string]string{}}
+	if _, err := toml.DecodeFile(filepath.Join(dir, PackFile), &p.Meta); err != nil {
+		return nil, fmt.Errorf("%s: %w", PackFile, err)
+	}
+	indexPath := filepath.Join(dir, filepath.FromSlash(p.Meta.Index.File))
+	if p.Meta.Index.Hash != "" {
+		if err := checkHash(indexPath, p.Meta.Index.HashFormat, p.Meta.Index.Hash); err != nil {
+			return nil, err
+		}
+	}
+	var idx Index
+	if _, err := toml.DecodeFile(indexPath, &idx); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Meta.Index.File, err)
+	}
+
+	// index paths are relative to the index file
+	base := path.Dir(p.Meta.Index.File)
+	for _, f := range idx.Files {
+		rel := path.Join(base, f.File)
+		local := filepath.Join(dir, filepath.FromSlash(rel))
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return nil, fmt.Errorf("%s: %q is outside the pack", IndexFile, f.File)
+		}
+		format := f.HashFormat
+		if format == "" {
+			format = idx.HashFormat
+		}
+		if err := checkHash(local, format, f.Hash); err != nil {
+			return nil, err
+		}
+		if f.Metafile || strings.HasSuffix(rel, metaSuffix) {
+			var m Metafile
+			if _, err := toml.DecodeFile(local, &m); err != nil {
+				return nil, fmt.Errorf("%s: %w", rel, err)
+			}
+			p.Metafiles[rel] = m
+			continue
+		}
+		p.Files[rel] = local
+	}
+	return p, nil
+}
+
+// @brief Minecraft reads the game and loader versions from pack.toml.
+// @return Minecraft header; Loader is empty for a vanilla pack
+func (p *Pack) Minecraft() manifest.Minecraft {
+	mc := manifest.Minecraft{Version: p.Meta.Versions["minecraft"]}
+	for loader, key := range loaderKeys {
+		if v, ok := p.Meta.Versions[key]; ok {
+			mc.Loader, mc.LoaderVersion = loader, v
+		}
+	}
+	return mc
+}
+
+// @brief Entry converts a metafile to a manifest entry, keeping its version pin.
+// @param rel slash path of the metafile, which decides content type and destination
+// @return content type, entry, or error if the file cannot be represented
+func (m Metafile) Entry(rel string) (manifest.ContentType, manifest.Entry, error) {
+	if !filepath.IsLocal(m.Filename) || strings.ContainsAny(m.Filename, `/\`) {
+		return manifest.ContentType{}, manifest.Entry{}, fmt.Errorf("%s: filename %q is not a plain file name", rel, m.Filename)
+	}
+	ct, dest, world := manifest.TypeForPath(path.Join(path.Dir(rel), m.Filename))
+	if !filepath.IsLocal(filepath.FromSlash(dest)) {
+		return ct, manifest.Entry{}, fmt.Errorf("%s: folder %q is outside the game dir", rel, dest)
+	}
+	e := manifest.Entry{
+		Slug:     strings.TrimSuffix(path.Base(rel), metaSuffix),
+		Dest:     dest,
+		Filename: m.Filename,
+		Enable:   m.Option == nil || !m.Option.Optional || m.Option.Default,
+		Pinned:   true,
+	}
+	if world != "" {
+		e.Worlds = []string{world}
+	}
+	switch strings.ToLower(m.Download.HashFormat) {
+	case checksum.SHA1:
+		e.Checksum = strings.ToLower(m.Download.Hash)
+	case checksum.SHA512:
+		e.SHA512 = strings.ToLower(m.Download.Hash)
+	}
+	switch m.Side {
+	case "client":
+		e.ClientSide, e.ServerSide = "required", "unsupported"
+	case "server":
+		e.ClientSide, e.ServerSide = "unsupported", "required"
+	}
+
+	switch {
+	case m.Update != nil && m.Update.Modrinth != nil:
+		e.ProjectID = m.Update.Modrinth.ModID
+		e.Version = m.Update.Modrinth.Version
+	case m.Update != nil && m.Update.CurseForge != nil:
+		e.Source = source.CurseForge
+		e.ProjectID = strconv.Itoa(m.Update.CurseForge.ProjectID)
+		e.Version = strconv.Itoa(m.Update.CurseForge.FileID)
+	case m.Download.URL != "":
+		if e.Checksum == "" {
+			return ct, e, fmt.Errorf("%s: url download has a %s hash, a sha1 is needed", rel, m.Download.HashFormat)
+		}
+		e.Source = source.URL
+		e.URL = m.Download.URL
+		e.Version = e.Checksum
+		e.Pinned = false // the hash already pins it
+	default:
+		return ct, e, fmt.Errorf("%s: no download url or update source", rel)
+	}
+	return ct, e, nil
+}
+
+// @brief CopyFiles copies the pack's plain files (configs, embedded jars) into a folder.
+// @param dest folder to copy into, usually the manifest's overrides/
+// @return copied slash paths relative to dest, or error
+func (p *Pack) CopyFiles(dest string) ([]string, error) {
+	rels := make([]string, 0, len(p.Files))
+	for rel := range p.Files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		if err := fsutil.CopyFile(p.Files[rel], filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+			return nil, err
+		}
+	}
+	return rels, nil
+}
+
+/*
+--------------------------------------------------
+  WRITE
+--------------------------------------------------
+*/
+
+// @brief Write saves the pack: metafiles, plain files, index.toml and pack.toml.
+// index.toml and pack.toml get fresh sha256 hashes.
+// @param dir pack folder, created if needed
+// @return error if a file could not be written
+func (p *Pack) Write(dir string) error {
+	idx := Index{HashFormat: "sha256"}
+
+	for rel, m := range p.Metafiles {
+		dst := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := writeTOML(dst, m); err != nil {
+			return err
+		}
+		sum, err := checksum.FileWith(dst, "sha256")
+		if err != nil {
+			return err
+		}
+		idx.Files = append(idx.Files, IndexEntry{File: rel, Hash: sum, Metafile: true})
+	}
+	for rel, src := range p.Files {
+		dst := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := fsutil.CopyFile(src, dst); err != nil {
+			return err
+		}
+		sum, err := checksum.FileWith(dst, "sha256")
+		if err != nil {
+			return err
+		}
+		idx.Files = append(idx.Files, IndexEntry{File: rel, Hash: sum})
+	}
+	sort.Slice(idx.Files, func(i, j int) bool { return idx.Files[i].File < idx.Files[j].File })
+
+	indexPath := filepath.Join(dir, IndexFile)
+	if err := writeTOML(indexPath, idx); err != nil {
+		return err
+	}
+	sum, err := checksum.FileWith(indexPath, "sha256")
+	if err != nil {
+		return err
+	}
+	p.Meta.PackFormat = PackFormat
+	p.Meta.Index = IndexRef{File: IndexFile, HashFormat: "sha256", Hash: sum}
+	return writeTOML(filepath.Join(dir, PackFile), p.Meta)
+}
+
+// @brief SetMinecraft fills [versions] from the manifest header.
+// @param mc Minecraft header
+// @return error if packwiz has no such loader
+func (p *Pack) SetMinecraft(mc manifest.Minecraft) error {
+	p.Meta.Versions = map[string]string{"minecraft": mc.Version}
+	if mc.Loader == "" || mc.Loader == "vanilla" {
+		return nil
+	}
+	key, ok := loaderKeys[mc.Loader]
+	if !ok {
+		return fmt.Errorf("packwiz has no %s loader", mc.Loader)
+	}
+	if mc.LoaderVersion == "" || mc.LoaderVersion == "latest" {
+		return fmt.Errorf("loader version is %q, packwiz needs a concrete one", mc.LoaderVersion)
+	}
+	p.Meta.Versions[key] = mc.LoaderVersion
+	return nil
+}
+
+// @brief MetaPath is where the metafile of an entry goes.
+// @param e manifest entry
+// @return slash path relative to the pack ("mods/sodium.pw.toml")
+func MetaPath(e manifest.Entry) string {
+	return path.Join(filepath.ToSlash(e.Dest), e.Slug+metaSuffix)
+}
+
+// @brief SideOf converts recorded side support to packwiz's side field.
+// @return "client", "server" or "both"
+func SideOf(e manifest.Entry) string {
+	switch e.SideLabel() {
+	case manifest.SideClient, manifest.SideServer:
+		return e.SideLabel()
+	default:
+		return "both"
+	}
+}
+
+/*
+--------------------------------------------------
+  LOW-LEVEL UTILS
+--------------------------------------------------
+*/
+
+func writeTOML(dst string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func checkHash(p, format, want string) error {
+	got, err := checksum.FileWith(p, format)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("%s: %s mismatch (index says %s, file is %s)", filepath.Base(p), format, want, got)
+	}
+	return nil
+}
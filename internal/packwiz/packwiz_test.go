@@ -0,0 +1,139 @@
+package packwiz
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/source"
+)
+
+func TestWriteRead(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "options.txt")
+	if err := os.WriteFile(src, []byte("fov:90\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &Pack{
+		Meta: PackMeta{Name: "Test", Author: "someone", Version: "1.0"},
+		Metafiles: map[string]Metafile{
+			"mods/sodium.pw.toml": {
+				Name:     "Sodium",
+				Filename: "sodium-0.5.jar",
+				Side:     "client",
+				Download: Download{URL: "https://cdn.modrinth.com/sodium-0.5.jar", HashFormat: "sha1", Hash: "aa"},
+				Update:   &Update{Modrinth: &ModrinthUpdate{ModID: "AANobbMI", Version: "v1"}},
+			},
+			"mods/jei.pw.toml": {
+				Name:     "JEI",
+				Filename: "jei.jar",
+				Download: Download{HashFormat: "sha1", Hash: "bb", Mode: "metadata:curseforge"},
+				Option:   &Option{Optional: true, Default: false},
+				Update:   &Update{CurseForge: &CurseForgeUpdate{FileID: 2, ProjectID: 1}},
+			},
+		},
+		Files: map[string]string{"config/options.txt": src},
+	}
+	if err := p.SetMinecraft(manifest.Minecraft{Version: "1.20.1", Loader: "fabric", LoaderVersion: "0.15.7"}); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := p.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Metafiles, p.Metafiles) {
+		t.Errorf("metafiles = %+v, want %+v", got.Metafiles, p.Metafiles)
+	}
+	if want := (manifest.Minecraft{Version: "1.20.1", Loader: "fabric", LoaderVersion: "0.15.7"}); got.Minecraft() != want {
+		t.Errorf("Minecraft() = %+v, want %+v", got.Minecraft(), want)
+	}
+	b, err := os.ReadFile(got.Files["config/options.txt"])
+	if err != nil || string(b) != "fov:90\n" {
+		t.Errorf("config/options.txt = %q, %v", b, err)
+	}
+
+	// a file edited after the index was written is refused
+	if err := os.WriteFile(filepath.Join(dir, "config", "options.txt"), []byte("fov:70\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(dir); err == nil {
+		t.Error("Read accepted a file that does not match its index hash")
+	}
+}
+
+func TestMetafileEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		rel     string
+		m       Metafile
+		want    manifest.Entry
+		wantErr bool
+	}{
+		{
+			name: "modrinth",
+			rel:  "mods/sodium.pw.toml",
+			m: Metafile{Filename: "sodium.jar", Side: "client",
+				Download: Download{URL: "https://cdn.modrinth.com/x.jar", HashFormat: "sha512", Hash: "CC"},
+				Update:   &Update{Modrinth: &ModrinthUpdate{ModID: "P1", Version: "v1"}}},
+			want: manifest.Entry{Slug: "sodium", Dest: "mods", Filename: "sodium.jar", Enable: true, Pinned: true,
+				SHA512: "cc", ClientSide: "required", ServerSide: "unsupported", ProjectID: "P1", Version: "v1"},
+		},
+		{
+			name: "curseforge optional",
+			rel:  "mods/jei.pw.toml",
+			m: Metafile{Filename: "jei.jar", Option: &Option{Optional: true},
+				Download: Download{HashFormat: "sha1", Hash: "aa"},
+				Update:   &Update{CurseForge: &CurseForgeUpdate{FileID: 2, ProjectID: 1}}},
+			want: manifest.Entry{Slug: "jei", Dest: "mods", Filename: "jei.jar", Pinned: true, Checksum: "aa",
+				Source: source.CurseForge, ProjectID: "1", Version: "2"},
+		},
+		{
+			name: "url",
+			rel:  "resourcepacks/faithful.pw.toml",
+			m:    Metafile{Filename: "faithful.zip", Download: Download{URL: "https://example.com/f.zip", HashFormat: "sha1", Hash: "dd"}},
+			want: manifest.Entry{Slug: "faithful", Dest: "resourcepacks", Filename: "faithful.zip", Enable: true, Checksum: "dd",
+				Source: source.URL, URL: "https://example.com/f.zip", Version: "dd"},
+		},
+		{
+			name:    "url without sha1",
+			rel:     "mods/x.pw.toml",
+			m:       Metafile{Filename: "x.jar", Download: Download{URL: "https://example.com/x.jar", HashFormat: "sha256", Hash: "ee"}},
+			wantErr: true,
+		},
+		{
+			name:    "nothing to download",
+			rel:     "mods/y.pw.toml",
+			m:       Metafile{Filename: "y.jar", Download: Download{HashFormat: "sha1", Hash: "ff"}},
+			wantErr: true,
+		},
+		{
+			name:    "filename leaves the folder",
+			rel:     "mods/evil.pw.toml",
+			m:       Metafile{Filename: "../../../evil.jar", Update: &Update{Modrinth: &ModrinthUpdate{ModID: "AANobbMI", Version: "v1"}}},
+			wantErr: true,
+		},
+		{
+			name:    "filename is a folder",
+			rel:     "mods/dots.pw.toml",
+			m:       Metafile{Filename: "..", Update: &Update{Modrinth: &ModrinthUpdate{ModID: "AANobbMI", Version: "v1"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := tt.m.Entry(tt.rel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entry = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
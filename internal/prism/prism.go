@@ -0,0 +1,182 @@
+package prism
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+)
+
+// files at the root of a Prism Launcher / MultiMC instance
+const (
+	InstanceFile = "instance.cfg"
+	PackFile     = "mmc-pack.json"
+)
+
+// component uids
+const (
+	uidMinecraft    = "net.minecraft"
+	uidIntermediary = "net.fabricmc.intermediary"
+)
+
+// loaderUIDs maps manifest loaders to their component uid.
+var loaderUIDs = map[string]string{
+	"fabric":   "net.fabricmc.fabric-loader",
+	"quilt":    "org.quiltmc.quilt-loader",
+	"forge":    "net.minecraftforge",
+	"neoforge": "net.neoforged",
+}
+
+// Pack is mmc-pack.json.
+type Pack struct {
+	Components    []Component `json:"components"`
+	FormatVersion int         `json:"formatVersion"`
+}
+
+// Component is one entry of mmc-pack.json.
+type Component struct {
+	UID       string `json:"uid"`
+	Version   string `json:"version"`
+	Important bool   `json:"important,omitempty"`
+}
+
+// @brief IsInstance reports whether a folder is an instance root.
+// @param dir folder to check
+// @return true if it holds instance.cfg or mmc-pack.json
+func IsInstance(dir string) bool {
+	for _, f := range []string{InstanceFile, PackFile} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// @brief GameDir returns the game folder of an instance.
+// Prism uses ".minecraft", older MultiMC instances "minecraft".
+// @param dir instance root
+// @return game folder and true, or false if dir is not an instance
+func GameDir(dir string) (string, bool) {
+	if !IsInstance(dir) {
+		return "", false
+	}
+	for _, sub := range []string{".minecraft", "minecraft"} {
+		p := filepath.Join(dir, sub)
+		if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+			return p, true
+		}
+	}
+	return filepath.Join(dir, ".minecraft"), true
+}
+
+// @brief ReadMinecraft reads the game and loader versions from mmc-pack.json.
+// @param dir instance root
+// @return versions (Loader "vanilla" if there is none) or error
+func ReadMinecraft(dir string) (manifest.Minecraft, error) {
+	var mc manifest.Minecraft
+	b, err := os.ReadFile(filepath.Join(dir, PackFile))
+	if err != nil {
+		return mc, err
+	}
+	var p Pack
+	if err := json.Unmarshal(b, &p); err != nil {
+		return mc, fmt.Errorf("%s: %w", PackFile, err)
+	}
+	mc.Loader = "vanilla"
+	for _, c := range p.Components {
+		if c.UID == uidMinecraft {
+			mc.Version = c.Version
+			continue
+		}
+		for loader, uid := range loaderUIDs {
+			if c.UID == uid {
+				mc.Loader, mc.LoaderVersion = loader, c.Version
+			}
+		}
+	}
+	return mc, nil
+}
+
+// @brief Mismatches compares an instance's versions with the manifest's.
+// A manifest loader version of "" or "latest" matches anything.
+// @param inst versions from mmc-pack.json
+// @param want versions from the manifest
+// @return one line per difference, empty if they agree
+func Mismatches(inst, want manifest.Minecraft) []string {
+	var out []string
+	if inst.Version != want.Version {
+		out = append(out, fmt.Sprintf("instance runs minecraft %s, manifest wants %s", inst.Version, want.Version))
+	}
+	if inst.Loader != want.Loader {
+		out = append(out, fmt.Sprintf("instance uses %s, manifest wants %s", inst.Loader, want.Loader))
+	} else if want.LoaderVersion != "" && want.LoaderVersion != "latest" && inst.LoaderVersion != want.LoaderVersion {
+		out = append(out, fmt.Sprintf("instance has %s %s, manifest wants %s", inst.Loader, inst.LoaderVersion, want.LoaderVersion))
+	}
+	return out
+}
+
+// @brief Create writes a new instance: instance.cfg, mmc-pack.json and an empty game folder.
+// @param dir instance root, must not exist yet
+// @param name instance name shown in the launcher
+// @param mc versions to put in mmc-pack.json
+// @return game folder of the new instance, or error
+func Create(dir, name string, mc manifest.Minecraft) (string, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return "", fmt.Errorf("%s already exists", dir)
+	}
+	p := Pack{FormatVersion: 1, Components: []Component{{UID: uidMinecraft, Version: mc.Version, Important: true}}}
+	if mc.Loader != "" && mc.Loader != "vanilla" {
+		uid, ok := loaderUIDs[mc.Loader]
+		if !ok {
+			return "", fmt.Errorf("prism has no %s loader", mc.Loader)
+		}
+		if mc.LoaderVersion == "" || mc.LoaderVersion == "latest" {
+			return "", fmt.Errorf("loader version is %q, prism needs a concrete one", mc.LoaderVersion)
+		}
+		if mc.Loader == "fabric" || mc.Loader == "quilt" {
+			p.Components = append(p.Components, Component{UID: uidIntermediary, Version: mc.Version})
+		}
+		p.Components = append(p.Components, Component{UID: uid, Version: mc.LoaderVersion})
+	}
+
+	game := filepath.Join(dir, ".minecraft")
+	if err := os.MkdirAll(game, 0o755); err != nil {
+		return "", err
+	}
+	b, _ := json.MarshalIndent(p, "", "    ")
+	if err := os.WriteFile(filepath.Join(dir, PackFile), b, 0o644); err != nil {
+		return "", err
+	}
+	cfg := "InstanceType=OneSix\nname=" + strings.ReplaceAll(name, "\n", " ") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, InstanceFile), []byte(cfg), 0o644); err != nil {
+		return "", err
+	}
+	return game, nil
+}
+
+// @brief InstancesDir returns Prism Launcher's default instances folder.
+// @return folder or error if the user data dir is unknown
+func InstancesDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if d := os.Getenv("APPDATA"); d != "" {
+			return filepath.Join(d, "PrismLauncher", "instances"), nil
+		}
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, "Library", "Application Support", "PrismLauncher", "instances"), nil
+		}
+	default:
+		if d := os.Getenv("XDG_DATA_HOME"); d != "" {
+			return filepath.Join(d, "PrismLauncher", "instances"), nil
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "PrismLauncher", "instances"), nil
+		}
+	}
+	return "", fmt.Errorf("cannot find Prism's data folder, pass --instances")
+}
@@ -0,0 +1,211 @@
+package serverpack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/jarmeta"
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+)
+
+// download endpoints, variables so they can point at a mirror
+var (
+	FabricMeta     = "https://meta.fabricmc.net/v2/"
+	QuiltInstaller = "https://quiltmc.org/api/v1/download-latest-installer/java-universal"
+	ForgeMaven     = "https://maven.minecraftforge.net/net/minecraftforge/forge/"
+	NeoForgeMaven  = "https://maven.neoforged.net/releases/net/neoforged/neoforge/"
+	MojangManifest = "https://piston-meta.mojang.com/mc/game/version_manifest_v2.json"
+)
+
+// Launcher is what starts the server for a loader.
+type Launcher struct {
+	Loader string
+	File   string // file name in the pack
+	URL    string // where File comes from, empty if the user has to supply it
+	// Install is the java argument list that turns File into a runnable
+	// server on first start (Forge, NeoForge and Quilt installers), empty if
+	// File runs as is.
+	Install []string
+	// Jar is started with "java -jar" after installing; empty means the
+	// installer wrote run.sh / run.bat (Forge since 1.17, NeoForge).
+	Jar string
+}
+
+// versionPattern is what game and loader versions may look like. They end up
+// in the start scripts, so anything a shell would interpret is refused.
+var versionPattern = regexp.MustCompile(`^[0-9A-Za-z.+_-]+$`)
+
+// @brief ResolveLauncher finds the server launcher for a game and loader version.
+// @param ctx context for cancellation
+// @param hc http client for the metadata lookups
+// @param mc versions from the manifest; LoaderVersion must be concrete
+// @return launcher or error if the loader has none, a version is malformed
+// or a lookup failed
+func ResolveLauncher(ctx context.Context, hc *http.Client, mc manifest.Minecraft) (*Launcher, error) {
+	lv := mc.LoaderVersion
+	needsVersion := mc.Loader != "" && mc.Loader != "vanilla" && !manifest.IsPluginPlatform(mc.Loader)
+	if needsVersion && (lv == "" || lv == "latest") {
+		return nil, fmt.Errorf("loader version is %q, pass a concrete one with --loader-version", lv)
+	}
+	if !versionPattern.MatchString(mc.Version) {
+		return nil, fmt.Errorf("malformed minecraft version %q", mc.Version)
+	}
+	if mc.Loader != "" && !versionPattern.MatchString(mc.Loader) {
+		return nil, fmt.Errorf("malformed loader %q", mc.Loader)
+	}
+	if needsVersion && !versionPattern.MatchString(lv) {
+		return nil, fmt.Errorf("malformed loader version %q", lv)
+	}
+
+	l, err := resolveLauncher(ctx, hc, mc)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(l.URL, "\"\r\n\t ") {
+		return nil, fmt.Errorf("refusing launcher url %q", l.URL)
+	}
+	return l, nil
+}
+
+func resolveLauncher(ctx context.Context, hc *http.Client, mc manifest.Minecraft) (*Launcher, error) {
+	lv := mc.LoaderVersion
+	switch mc.Loader {
+	case "fabric":
+		inst, err := fabricInstaller(ctx, hc)
+		if err != nil {
+			return nil, fmt.Errorf("fabric installer lookup failed: %w", err)
+		}
+		return &Launcher{
+			Loader: mc.Loader,
+			File:   "fabric-server-launch.jar",
+			URL:    fmt.Sprintf("%sversions/loader/%s/%s/%s/server/jar", FabricMeta, url.PathEscape(mc.Version), url.PathEscape(lv), url.PathEscape(inst)),
+			Jar:    "fabric-server-launch.jar",
+		}, nil
+	case "quilt":
+		return &Launcher{
+			Loader:  mc.Loader,
+			File:    "quilt-installer.jar",
+			URL:     QuiltInstaller,
+			Install: []string{"-jar", "quilt-installer.jar", "install", "server", mc.Version, lv, "--download-server", "--install-dir=."},
+			Jar:     "quilt-server-launch.jar",
+		}, nil
+	case "forge":
+		jar, err := forgeJar(mc.Version, lv)
+		if err != nil {
+			return nil, err
+		}
+		full := mc.Version + "-" + lv
+		return &Launcher{
+			Loader:  mc.Loader,
+			File:    "forge-installer.jar",
+			URL:     fmt.Sprintf("%s%s/forge-%s-installer.jar", ForgeMaven, full, full),
+			Install: []string{"-jar", "forge-installer.jar", "--installServer"},
+			Jar:     jar,
+		}, nil
+	case "neoforge":
+		return &Launcher{
+			Loader:  mc.Loader,
+			File:    "neoforge-installer.jar",
+			URL:     fmt.Sprintf("%s%s/neoforge-%s-installer.jar", NeoForgeMaven, lv, lv),
+			Install: []string{"-jar", "neoforge-installer.jar", "--installServer"},
+		}, nil
+	case "", "vanilla":
+		u, err := vanillaServer(ctx, hc, mc.Version)
+		if err != nil {
+			return nil, fmt.Errorf("minecraft server lookup failed: %w", err)
+		}
+		return &Launcher{Loader: "vanilla", File: "server.jar", URL: u, Jar: "server.jar"}, nil
+	default:
+		// plugin platforms publish builds their own way; the user drops the jar in
+		return &Launcher{Loader: mc.Loader, File: "server.jar", Jar: "server.jar"}, nil
+	}
+}
+
+// forgeJar returns the server jar the Forge installer writes, or "" from 1.17
+// on, where it writes run.sh instead. Before 1.12.2 build 2847 the jar is the
+// "-universal" one; before 1.10 Forge's maven paths carry the game version
+// twice and are not supported.
+func forgeJar(mcVersion, lv string) (string, error) {
+	full := mcVersion + "-" + lv
+	switch {
+	case jarmeta.Compare(mcVersion, "1.17") >= 0:
+		return "", nil
+	case jarmeta.Compare(mcVersion, "1.10") < 0:
+		return "", fmt.Errorf("forge server packs need minecraft 1.10 or newer, not %s", mcVersion)
+	case jarmeta.Compare(mcVersion, "1.12.2") < 0,
+		mcVersion == "1.12.2" && jarmeta.Compare(lv, "14.23.5.2847") < 0:
+		return "forge-" + full + "-universal.jar", nil
+	}
+	return "forge-" + full + ".jar", nil
+}
+
+// fabricInstaller returns the newest stable Fabric installer version.
+func fabricInstaller(ctx context.Context, hc *http.Client) (string, error) {
+	var vs []struct {
+		Version string `json:"version"`
+		Stable  bool   `json:"stable"`
+	}
+	if err := getJSON(ctx, hc, FabricMeta+"versions/installer", &vs); err != nil {
+		return "", err
+	}
+	for _, v := range vs {
+		if v.Stable {
+			return v.Version, nil
+		}
+	}
+	return "", fmt.Errorf("no stable installer listed")
+}
+
+// vanillaServer returns the server.jar url of a game version.
+func vanillaServer(ctx context.Context, hc *http.Client, version string) (string, error) {
+	var idx struct {
+		Versions []struct {
+			ID  string `json:"id"`
+			URL string `json:"url"`
+		} `json:"versions"`
+	}
+	if err := getJSON(ctx, hc, MojangManifest, &idx); err != nil {
+		return "", err
+	}
+	for _, v := range idx.Versions {
+		if v.ID != version {
+			continue
+		}
+		var meta struct {
+			Downloads struct {
+				Server struct {
+					URL string `json:"url"`
+				} `json:"server"`
+			} `json:"downloads"`
+		}
+		if err := getJSON(ctx, hc, v.URL, &meta); err != nil {
+			return "", err
+		}
+		if meta.Downloads.Server.URL == "" {
+			return "", fmt.Errorf("%s has no server download", version)
+		}
+		return meta.Downloads.Server.URL, nil
+	}
+	return "", fmt.Errorf("unknown minecraft version %s", version)
+}
+
+func getJSON(ctx context.Context, hc *http.Client, u string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", u, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(dest)
+}
@@ -0,0 +1,50 @@
+package serverpack
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+)
+
+func TestForgeJar(t *testing.T) {
+	tests := []struct {
+		mc, lv  string
+		want    string
+		wantErr bool
+	}{
+		{mc: "1.20.1", lv: "47.2.0", want: ""},
+		{mc: "1.17.1", lv: "37.1.1", want: ""},
+		{mc: "1.16.5", lv: "36.2.39", want: "forge-1.16.5-36.2.39.jar"},
+		{mc: "1.12.2", lv: "14.23.5.2860", want: "forge-1.12.2-14.23.5.2860.jar"},
+		{mc: "1.12.2", lv: "14.23.5.2838", want: "forge-1.12.2-14.23.5.2838-universal.jar"},
+		{mc: "1.10.2", lv: "12.18.3.2511", want: "forge-1.10.2-12.18.3.2511-universal.jar"},
+		{mc: "1.7.10", lv: "10.13.4.1614", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := forgeJar(tt.mc, tt.lv)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("forgeJar(%q, %q) error = %v, wantErr %v", tt.mc, tt.lv, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("forgeJar(%q, %q) = %q, want %q", tt.mc, tt.lv, got, tt.want)
+		}
+	}
+}
+
+func TestResolveLauncherMalformed(t *testing.T) {
+	tests := []manifest.Minecraft{
+		{Version: "1.20.1", Loader: "forge", LoaderVersion: "47.2.0;rm -rf ~"},
+		{Version: "1.20.1", Loader: "neoforge", LoaderVersion: "20.1'&calc"},
+		{Version: "1.20.1$(id)", Loader: "fabric", LoaderVersion: "0.15.0"},
+		{Version: "1.20.1", Loader: "paper\necho", LoaderVersion: ""},
+	}
+	for _, mc := range tests {
+		// refused before any lookup, so the client is never used
+		if _, err := ResolveLauncher(context.Background(), http.DefaultClient, mc); err == nil {
+			t.Errorf("ResolveLauncher(%+v) accepted a malformed version", mc)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package serverpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultJVMArgs is used when the user passes none.
+const DefaultJVMArgs = "-Xms1G -Xmx4G"
+
+// Pack is a ready-to-run server folder.
+type Pack struct {
+	Files    map[string]string // slash path in the pack -> file on disk
+	Launcher *Launcher
+	// LauncherFile is the downloaded Launcher.File; empty leaves the download
+	// to the start scripts.
+	LauncherFile string
+	JVMArgs      string
+}
+
+// @brief Write writes the pack as a folder, or as a zip when dest ends in ".zip".
+// @param dest output folder (must not exist or be empty) or zip file
+// @return error if writing failed; a partial zip is removed
+func (p *Pack) Write(dest string) error {
+	if strings.EqualFold(filepath.Ext(dest), ".zip") {
+		out, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		zw := zip.NewWriter(out)
+		if err := p.write(zipSink{zw}); err != nil {
+			zw.Close()
+			out.Close()
+			os.Remove(dest)
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+
+	if err := CheckDest(dest); err != nil {
+		return err
+	}
+	return p.write(dirSink(dest))
+}
+
+// @brief CheckDest refuses to write a folder pack over existing files.
+// @param dest output folder or zip file
+// @return error if dest is a folder that is not empty
+func CheckDest(dest string) error {
+	if strings.EqualFold(filepath.Ext(dest), ".zip") {
+		return nil
+	}
+	if files, err := os.ReadDir(dest); err == nil && len(files) > 0 {
+		return fmt.Errorf("%s is not empty", dest)
+	}
+	return nil
+}
+
+func (p *Pack) write(s sink) error {
+	names := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := s.file(name, p.Files[name]); err != nil {
+			return err
+		}
+	}
+	if p.LauncherFile != "" {
+		if err := s.file(p.Launcher.File, p.LauncherFile); err != nil {
+			return err
+		}
+	}
+	jvm := p.JVMArgs
+	if jvm == "" {
+		jvm = DefaultJVMArgs
+	}
+	if err := CheckJVMArgs(jvm); err != nil {
+		return err
+	}
+	if err := s.bytes("start.sh", []byte(shScript(p.Launcher, jvm)), 0o755); err != nil {
+		return err
+	}
+	return s.bytes("start.bat", []byte(batScript(p.Launcher, jvm)), 0o644)
+}
+
+// shScript writes start.sh: fetch the launcher if missing, install once, run.
+// Every value from the pack is single-quoted.
+func shScript(l *Launcher, jvm string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Written by 'mod export server-pack'. Override the JVM arguments with JVM_ARGS=... ./start.sh\n")
+	b.WriteString("cd \"$(dirname \"$0\")\" || exit 1\n")
+	fmt.Fprintf(&b, "DEFAULT_JVM_ARGS=%s\nJVM_ARGS=\"${JVM_ARGS:-$DEFAULT_JVM_ARGS}\"\n\n", shQuote(jvm))
+	file := shQuote(l.File)
+	if l.URL != "" {
+		fmt.Fprintf(&b, "if [ ! -f %s ]; then\n\techo %s\n\tcurl -fLo %s %s || exit 1\nfi\n",
+			file, shQuote("Downloading "+l.File+"..."), file, shQuote(l.URL))
+	} else {
+		fmt.Fprintf(&b, "if [ ! -f %s ]; then\n\techo %s\n\texit 1\nfi\n",
+			file, shQuote("Put the "+l.Loader+" server jar here as "+l.File+" first."))
+	}
+	if len(l.Install) > 0 {
+		args := make([]string, len(l.Install))
+		for i, a := range l.Install {
+			args[i] = shQuote(a)
+		}
+		fmt.Fprintf(&b, "if [ ! -f %s ]; then\n\tjava %s || exit 1\nfi\n", shQuote(installed(l, "run.sh")), strings.Join(args, " "))
+	}
+	if l.Jar != "" {
+		fmt.Fprintf(&b, "\nexec java $JVM_ARGS -jar %s nogui \"$@\"\n", shQuote(l.Jar))
+	} else {
+		b.WriteString("\necho \"$JVM_ARGS\" > user_jvm_args.txt\nexec sh ./run.sh nogui \"$@\"\n")
+	}
+	return b.String()
+}
+
+// batScript writes start.bat, the same steps for Windows. Every value from
+// the pack is double-quoted with its percent signs doubled; echoed messages
+// only hold names ResolveLauncher already checked.
+func batScript(l *Launcher, jvm string) string {
+	var b strings.Builder
+	b.WriteString("@echo off\r\n")
+	b.WriteString("rem Written by 'mod export server-pack'. Override the JVM arguments with set JVM_ARGS=...\r\n")
+	b.WriteString("cd /d \"%~dp0\"\r\n")
+	fmt.Fprintf(&b, "if \"%%JVM_ARGS%%\"==\"\" set \"JVM_ARGS=%s\"\r\n\r\n", batEscape(jvm))
+	file := batQuote(l.File)
+	if l.URL != "" {
+		fmt.Fprintf(&b, "if not exist %s (\r\n  echo %s\r\n  curl -fLo %s %s || exit /b 1\r\n)\r\n",
+			file, batEscape("Downloading "+l.File+"..."), file, batQuote(l.URL))
+	} else {
+		fmt.Fprintf(&b, "if not exist %s (\r\n  echo %s\r\n  exit /b 1\r\n)\r\n",
+			file, batEscape("Put the "+l.Loader+" server jar here as "+l.File+" first."))
+	}
+	if len(l.Install) > 0 {
+		args := make([]string, len(l.Install))
+		for i, a := range l.Install {
+			args[i] = batQuote(a)
+		}
+		fmt.Fprintf(&b, "if not exist %s (\r\n  java %s || exit /b 1\r\n)\r\n", batQuote(installed(l, "run.bat")), strings.Join(args, " "))
+	}
+	if l.Jar != "" {
+		fmt.Fprintf(&b, "\r\njava %%JVM_ARGS%% -jar %s nogui %%*\r\n", batQuote(l.Jar))
+	} else {
+		b.WriteString("\r\necho %JVM_ARGS%> user_jvm_args.txt\r\ncall run.bat nogui %*\r\n")
+	}
+	return b.String()
+}
+
+// shQuote quotes a value for sh, closing and reopening the quotes around
+// any single quote in it.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// batQuote quotes a value for cmd.exe. Double quotes and line breaks cannot
+// be escaped there; ResolveLauncher and CheckJVMArgs keep them out.
+func batQuote(s string) string {
+	return `"` + batEscape(s) + `"`
+}
+
+func batEscape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// @brief CheckJVMArgs refuses JVM arguments the start scripts cannot quote.
+// @return error if they hold a double quote or a line break
+func CheckJVMArgs(jvm string) error {
+	if strings.ContainsAny(jvm, "\"\r\n") {
+		return fmt.Errorf("jvm arguments %q must not contain double quotes or line breaks", jvm)
+	}
+	return nil
+}
+
+// installed is the file whose presence means the installer already ran.
+func installed(l *Launcher, runScript string) string {
+	if l.Jar != "" {
+		return l.Jar
+	}
+	return runScript
+}
+
+// sink receives pack files, for a folder or a zip.
+type sink interface {
+	file(name, src string) error
+	bytes(name string, data []byte, mode fs.FileMode) error
+}
+
+type dirSink string
+
+func (d dirSink) file(name, src string) error {
+	dst := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func (d dirSink) bytes(name string, data []byte, mode fs.FileMode) error {
+	dst := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, mode)
+}
+
+type zipSink struct{ zw *zip.Writer }
+
+func (z zipSink) file(name, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	w, err := z.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, in)
+	return err
+}
+
+func (z zipSink) bytes(name string, data []byte, mode fs.FileMode) error {
+	h := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	h.SetMode(mode)
+	w, err := z.zw.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
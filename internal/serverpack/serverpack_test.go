@@ -0,0 +1,38 @@
+package serverpack
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestShQuote(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh")
+	}
+	for _, s := range []string{"plain", "it's", "'; touch pwned; '", "$(id) `id` \"x\" & y", ""} {
+		out, err := exec.Command(sh, "-c", "printf %s "+shQuote(s)).Output()
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if string(out) != s {
+			t.Errorf("shQuote(%q) reads back as %q", s, out)
+		}
+	}
+}
+
+func TestBatQuote(t *testing.T) {
+	tests := map[string]string{
+		"forge-installer.jar":       `"forge-installer.jar"`,
+		"https://x.test/a%20b&c=d":  `"https://x.test/a%%20b&c=d"`,
+		"Downloading server.jar...": `"Downloading server.jar..."`,
+	}
+	for in, want := range tests {
+		if got := batQuote(in); got != want {
+			t.Errorf("batQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+	if err := CheckJVMArgs("-Xmx4G\"& calc"); err == nil {
+		t.Error("CheckJVMArgs accepted a double quote")
+	}
+}
@@ -0,0 +1,120 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// GitHubClient looks up release assets through the GitHub REST API.
+type GitHubClient struct {
+	base  *url.URL
+	http  *http.Client
+	token string // optional, from $GITHUB_TOKEN, raises the rate limit
+}
+
+type ghRelease struct {
+	TagName string    `json:"tag_name"`
+	Name    string    `json:"name"`
+	Assets  []ghAsset `json:"assets"`
+}
+
+type ghAsset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+	Size        int64  `json:"size"`
+}
+
+// @brief NewGitHub creates a GitHub client.
+// @param base API base url ("https://api.github.com/")
+// @return client or error if the url is invalid
+func NewGitHub(base string) (*GitHubClient, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	return &GitHubClient{
+		base:  u,
+		http:  &http.Client{Timeout: 15 * time.Second},
+		token: os.Getenv("GITHUB_TOKEN"),
+	}, nil
+}
+
+// @brief Release finds the asset of a release whose name matches a pattern.
+// @param ctx context for cancellation
+// @param repo "owner/name"
+// @param tag release tag, empty for the latest release
+// @param pattern glob matched against asset names ("*-fabric-*.jar")
+// @return release pointing at the asset (hash unknown until downloaded) or error
+func (c *GitHubClient) Release(ctx context.Context, repo, tag, pattern string) (*Release, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("github repo %q must be owner/name", repo)
+	}
+	p := fmt.Sprintf("repos/%s/%s/releases/latest", url.PathEscape(owner), url.PathEscape(name))
+	if tag != "" {
+		p = fmt.Sprintf("repos/%s/%s/releases/tags/%s", url.PathEscape(owner), url.PathEscape(name), url.PathEscape(tag))
+	}
+
+	var rel ghRelease
+	if err := c.getJSON(ctx, p, &rel); err != nil {
+		return nil, err
+	}
+
+	if pattern == "" {
+		pattern = "*.jar"
+	}
+	for _, a := range rel.Assets {
+		ok, err := path.Match(pattern, a.Name)
+		if err != nil {
+			return nil, fmt.Errorf("bad asset pattern %q: %w", pattern, err)
+		}
+		if ok {
+			return &Release{
+				Version:       rel.TagName,
+				VersionNumber: rel.TagName,
+				Filename:      a.Name,
+				URL:           a.DownloadURL,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no asset matching %q in %s release %s", pattern, repo, rel.TagName)
+}
+
+// @brief getJSON performs an authenticated GET against the API.
+// @param ctx context for cancellation
+// @param p escaped path relative to the base url
+// @param dest destination to decode into
+// @return error if the request failed or the response could not be decoded
+func (c *GitHubClient) getJSON(ctx context.Context, p string, dest any) error {
+	ref, err := url.Parse(p) // keeps escapes like %2F in tags as they are
+	if err != nil {
+		return err
+	}
+	u := c.base.ResolveReference(ref)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", p, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
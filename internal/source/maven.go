@@ -0,0 +1,236 @@
+package source
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Coords are Maven coordinates: group:artifact[:version[:classifier]][@ext].
+type Coords struct {
+	Group      string
+	Artifact   string
+	Version    string // "", "latest", "release", "1.2.+" or a concrete version
+	Classifier string
+	Ext        string // default "jar"
+}
+
+// @brief ParseCoords parses group:artifact[:version[:classifier]][@ext].
+// @param s coordinate string, e.g. "net.fabricmc:fabric-loader:0.16.+"
+// @return coordinates or error if group or artifact is missing
+func ParseCoords(s string) (Coords, error) {
+	c := Coords{Ext: "jar"}
+	if base, ext, ok := strings.Cut(s, "@"); ok {
+		s, c.Ext = base, ext
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+		return Coords{}, fmt.Errorf("maven coordinates %q must be group:artifact[:version[:classifier]]", s)
+	}
+	c.Group, c.Artifact = parts[0], parts[1]
+	if len(parts) > 2 {
+		c.Version = parts[2]
+	}
+	if len(parts) > 3 {
+		c.Classifier = parts[3]
+	}
+	return c, nil
+}
+
+// @brief dir returns the artifact folder relative to the repository root.
+func (c Coords) dir() string {
+	return strings.ReplaceAll(c.Group, ".", "/") + "/" + c.Artifact
+}
+
+// @brief dynamic reports whether the version must be looked up in maven-metadata.xml.
+func (c Coords) dynamic() bool {
+	return c.Version == "" || c.Version == "latest" || c.Version == "release" || strings.HasSuffix(c.Version, "+")
+}
+
+type mavenMetadata struct {
+	Versioning struct {
+		Latest   string   `xml:"latest"`
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+		Snapshot struct {
+			Timestamp   string `xml:"timestamp"`
+			BuildNumber string `xml:"buildNumber"`
+		} `xml:"snapshot"`
+		SnapshotVersions []struct {
+			Classifier string `xml:"classifier"`
+			Extension  string `xml:"extension"`
+			Value      string `xml:"value"`
+		} `xml:"snapshotVersions>snapshotVersion"`
+	} `xml:"versioning"`
+}
+
+// MavenClient resolves artifacts in Maven repositories.
+type MavenClient struct {
+	http *http.Client
+}
+
+// @brief NewMaven creates a Maven client.
+func NewMaven() *MavenClient {
+	return &MavenClient{http: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// @brief Release resolves coordinates to a downloadable artifact.
+// Dynamic versions come from maven-metadata.xml, SNAPSHOT versions are mapped
+// to their newest timestamped build, and the hash comes from the .sha512
+// sidecar, or the .sha1 one when there is no .sha512.
+// @param ctx context for cancellation
+// @param repo repository root url ("https://maven.fabricmc.net/")
+// @param coords group:artifact[:version[:classifier]][@ext]
+// @return release or error
+func (c *MavenClient) Release(ctx context.Context, repo, coords string) (*Release, error) {
+	co, err := ParseCoords(coords)
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(strings.TrimSuffix(repo, "/") + "/")
+	if err != nil || base.Scheme != "https" || base.Host == "" {
+		return nil, fmt.Errorf("maven repository %q must be an https url", repo)
+	}
+
+	version := co.Version
+	if co.dynamic() {
+		if version, err = c.resolveVersion(ctx, base, co); err != nil {
+			return nil, err
+		}
+	}
+
+	// SNAPSHOT folders hold timestamped files
+	fileVersion := version
+	if strings.HasSuffix(version, "-SNAPSHOT") {
+		if fileVersion, err = c.snapshotVersion(ctx, base, co, version); err != nil {
+			return nil, err
+		}
+	}
+
+	name := co.Artifact + "-" + fileVersion
+	if co.Classifier != "" {
+		name += "-" + co.Classifier
+	}
+	name += "." + co.Ext
+	fileURL := base.ResolveReference(&url.URL{Path: co.dir() + "/" + version + "/" + name}).String()
+
+	rel := &Release{
+		Version:       fileVersion,
+		VersionNumber: fileVersion,
+		Filename:      name,
+		URL:           fileURL,
+	}
+	if rel.SHA512, err = c.sidecar(ctx, fileURL+".sha512"); err != nil {
+		return nil, err
+	}
+	if rel.SHA512 == "" {
+		if rel.SHA1, err = c.sidecar(ctx, fileURL+".sha1"); err != nil {
+			return nil, err
+		}
+	}
+	if rel.SHA1 == "" && rel.SHA512 == "" {
+		return nil, fmt.Errorf("no .sha512 or .sha1 published for %s", fileURL)
+	}
+	return rel, nil
+}
+
+// @brief resolveVersion picks a version from the artifact's maven-metadata.xml.
+func (c *MavenClient) resolveVersion(ctx context.Context, base *url.URL, co Coords) (string, error) {
+	var md mavenMetadata
+	if err := c.getXML(ctx, base.ResolveReference(&url.URL{Path: co.dir() + "/maven-metadata.xml"}), &md); err != nil {
+		return "", err
+	}
+	v := md.Versioning
+	switch {
+	case co.Version == "release" && v.Release != "":
+		return v.Release, nil
+	case strings.HasSuffix(co.Version, "+"):
+		prefix := strings.TrimSuffix(co.Version, "+")
+		for i := len(v.Versions) - 1; i >= 0; i-- { // listed oldest first
+			if strings.HasPrefix(v.Versions[i], prefix) {
+				return v.Versions[i], nil
+			}
+		}
+		return "", fmt.Errorf("no version of %s:%s matches %s", co.Group, co.Artifact, co.Version)
+	case v.Latest != "":
+		return v.Latest, nil
+	case v.Release != "":
+		return v.Release, nil
+	case len(v.Versions) > 0:
+		return v.Versions[len(v.Versions)-1], nil
+	}
+	return "", fmt.Errorf("maven-metadata.xml for %s:%s lists no versions", co.Group, co.Artifact)
+}
+
+// @brief snapshotVersion maps 1.0-SNAPSHOT to its newest timestamped build, 1.0-20250101.120000-3.
+func (c *MavenClient) snapshotVersion(ctx context.Context, base *url.URL, co Coords, version string) (string, error) {
+	var md mavenMetadata
+	u := base.ResolveReference(&url.URL{Path: co.dir() + "/" + version + "/maven-metadata.xml"})
+	if err := c.getXML(ctx, u, &md); err != nil {
+		return "", err
+	}
+	for _, sv := range md.Versioning.SnapshotVersions {
+		if sv.Classifier == co.Classifier && sv.Extension == co.Ext {
+			return sv.Value, nil
+		}
+	}
+	snap := md.Versioning.Snapshot
+	if snap.Timestamp == "" {
+		return version, nil // locally deployed snapshot without timestamps
+	}
+	return strings.TrimSuffix(version, "SNAPSHOT") + snap.Timestamp + "-" + snap.BuildNumber, nil
+}
+
+// @brief sidecar fetches a .sha1/.sha512 file.
+// @return the hash, "" if the repository does not publish one
+func (c *MavenClient) sidecar(ctx context.Context, u string) (string, error) {
+	body, err := c.get(ctx, u)
+	if err != nil || body == nil {
+		return "", err
+	}
+	// some repositories write "<hash>  <filename>"
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+func (c *MavenClient) getXML(ctx context.Context, u *url.URL, dest any) error {
+	body, err := c.get(ctx, u.String())
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return fmt.Errorf("GET %s: %w", u, ErrNotFound)
+	}
+	return xml.Unmarshal(body, dest)
+}
+
+// @brief get fetches a small file.
+// @return body, nil body on 404, or error
+func (c *MavenClient) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("GET %s: unexpected status %s", u, resp.Status)
+	}
+}
@@ -0,0 +1,130 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseCoords(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Coords
+		wantErr bool
+	}{
+		{in: "net.fabricmc:fabric-loader", want: Coords{Group: "net.fabricmc", Artifact: "fabric-loader", Ext: "jar"}},
+		{in: "net.fabricmc:fabric-loader:0.16.+", want: Coords{Group: "net.fabricmc", Artifact: "fabric-loader", Version: "0.16.+", Ext: "jar"}},
+		{in: "a.b:c:1.0:sources", want: Coords{Group: "a.b", Artifact: "c", Version: "1.0", Classifier: "sources", Ext: "jar"}},
+		{in: "a.b:c:1.0@zip", want: Coords{Group: "a.b", Artifact: "c", Version: "1.0", Ext: "zip"}},
+		{in: "a.b", wantErr: true},
+		{in: ":c", wantErr: true},
+		{in: "a:", wantErr: true},
+		{in: "a:b:c:d:e", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCoords(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCoords(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseCoords(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+const testMetadata = `<metadata>
+  <versioning>
+    <latest>2.0-beta</latest>
+    <release>1.3</release>
+    <versions>
+      <version>1.2.0</version>
+      <version>1.2.5</version>
+      <version>1.3</version>
+      <version>2.0-beta</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+func TestResolveVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/example/lib/maven-metadata.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testMetadata))
+	}))
+	defer srv.Close()
+	base, _ := url.Parse(srv.URL + "/")
+	c := &MavenClient{http: srv.Client()}
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "", want: "2.0-beta"},
+		{version: "latest", want: "2.0-beta"},
+		{version: "release", want: "1.3"},
+		{version: "1.2.+", want: "1.2.5"},
+		{version: "3.+", wantErr: true},
+	}
+	for _, tt := range tests {
+		co := Coords{Group: "org.example", Artifact: "lib", Version: tt.version, Ext: "jar"}
+		got, err := c.resolveVersion(context.Background(), base, co)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestMavenRelease(t *testing.T) {
+	files := map[string]string{
+		"/org/example/lib/1.0/lib-1.0.jar.sha512": "abc512  lib-1.0.jar\n",
+		"/org/example/lib/1.0/lib-1.0.jar.sha1":   "ABC1",
+		"/org/example/old/1.0/old-1.0.jar.sha1":   "def1",
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := &MavenClient{http: srv.Client()}
+	ctx := context.Background()
+
+	rel, err := c.Release(ctx, srv.URL, "org.example:lib:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.SHA512 != "abc512" || rel.SHA1 != "" {
+		t.Errorf("sha512 sidecar: got sha1=%q sha512=%q, want only sha512", rel.SHA1, rel.SHA512)
+	}
+	if want := srv.URL + "/org/example/lib/1.0/lib-1.0.jar"; rel.URL != want {
+		t.Errorf("URL = %q, want %q", rel.URL, want)
+	}
+
+	rel, err = c.Release(ctx, srv.URL, "org.example:old:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.SHA1 != "def1" {
+		t.Errorf("sha1 fallback: got %q", rel.SHA1)
+	}
+
+	if _, err := c.Release(ctx, srv.URL, "org.example:none:1.0"); err == nil {
+		t.Error("expected an error without sidecars")
+	}
+	if _, err := c.Release(ctx, "http://repo.example/", "org.example:lib:1.0"); err == nil {
+		t.Error("expected plain http repositories to be refused")
+	}
+}
@@ -0,0 +1,85 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+)
+
+// ErrNotFound is returned by providers when a project or file does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Provider is a hosting site projects can be searched, resolved and downloaded from.
+// modrinth.Client and curseforge.Client implement it.
+type Provider interface {
+	// Name is the value stored in Entry.Source ("modrinth", "curseforge").
+	Name() string
+	// SearchProjects runs a text search.
+	SearchProjects(ctx context.Context, query string, f Filter, limit int) ([]Project, error)
+	// LookupProject fetches one project by id or slug.
+	LookupProject(ctx context.Context, id string) (*Project, error)
+	// ListVersions lists versions matching the filter, newest first.
+	ListVersions(ctx context.Context, projectID string, f Filter) ([]Version, error)
+	// ResolveFiles lists the downloadable files of a version, primary first.
+	ResolveFiles(ctx context.Context, projectID, versionID string) ([]Release, error)
+	// VerifyFile checks a file on disk against a release's hashes.
+	VerifyFile(path string, rel *Release) error
+}
+
+// Filter narrows searches and version listings. Empty fields match anything.
+type Filter struct {
+	GameVersion  string   // "1.21.6"
+	Loader       string   // "fabric", "paper", "datapack"
+	ProjectTypes []string // content type names, searches only
+}
+
+// Project is the provider-neutral part of a project page.
+type Project struct {
+	ID           string
+	Slug         string
+	Title        string
+	Author       string
+	Description  string
+	ProjectType  string   // content type name as the provider reports it ("mod", "resourcepack")
+	Loaders      []string // supported loaders / platforms
+	GameVersions []string // supported Minecraft versions, when known
+	ClientSide   string   // "required" | "optional" | "unsupported" | "" (unknown)
+	ServerSide   string   // "required" | "optional" | "unsupported" | "" (unknown)
+	License      string   // SPDX id when known
+	LicenseName  string   // human-readable license name, when the provider has one
+	URL          string   // project page
+}
+
+// Version is one published version of a project.
+type Version struct {
+	ID           string
+	Number       string // human-readable
+	Published    string // RFC 3339, sorts chronologically as a string
+	GameVersions []string
+	Loaders      []string
+}
+
+// @brief VerifyHashes checks a file against every hash the release carries.
+// @param path file to check
+// @param rel release with the expected SHA1 and/or SHA512
+// @return nil if they all match, otherwise an error saying why
+func VerifyHashes(path string, rel *Release) error {
+	if rel.Sums().Empty() {
+		return fmt.Errorf("no hash known for %s", rel.Filename)
+	}
+	got, err := checksum.File(path)
+	if err != nil {
+		return err
+	}
+	if err := rel.Sums().Check(got); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// @brief Sums returns the hashes the release carries.
+func (r *Release) Sums() checksum.Sums {
+	return checksum.Sums{SHA1: r.SHA1, SHA512: r.SHA512}
+}
@@ -0,0 +1,101 @@
+package source
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+
+	"github.com/silask7188/ModrinthCLI/internal/checksum"
+)
+
+// entry sources; Entry.Source is left empty for Modrinth to keep old manifests as they were
+const (
+	Modrinth   = "modrinth"
+	CurseForge = "curseforge"
+	URL        = "url"
+	File       = "file"
+	GitHub     = "github"
+	Maven      = "maven"
+)
+
+// Release is one concrete file an entry resolves to, wherever it comes from.
+type Release struct {
+	Version       string // id recorded in Entry.Version
+	VersionNumber string // human-readable
+	Filename      string // name to place the file under
+	URL           string // remote download, or
+	Path          string // local file to copy
+	SHA1          string // expected hash, empty = trust and record on first install
+	SHA512        string // expected hash, when the source publishes one
+	Size          int64  // bytes, 0 if unknown
+}
+
+// @brief FromURL builds the release for a direct download.
+// The hash is mandatory: a URL gives no other guarantee about its content.
+// @param raw https URL
+// @param sha1sum expected SHA1 of the file
+// @return release or error if the URL is not https or the hash is missing
+func FromURL(raw, sha1sum string) (*Release, error) {
+	if err := CheckURL(raw); err != nil {
+		return nil, err
+	}
+	if sha1sum == "" {
+		return nil, fmt.Errorf("url source %s needs a sha1 hash", raw)
+	}
+	u, _ := url.Parse(raw)
+	return &Release{
+		Version:       sha1sum,
+		VersionNumber: shortHash(sha1sum),
+		Filename:      path.Base(u.Path),
+		URL:           raw,
+		SHA1:          sha1sum,
+	}, nil
+}
+
+// @brief CheckURL makes sure a download URL is absolute https.
+// @param raw URL to check
+// @return error if it is not
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("url %q must be absolute https", raw)
+	}
+	if path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+		return fmt.Errorf("url %q has no file name", raw)
+	}
+	return nil
+}
+
+// @brief FromFile builds the release for a local file copied into place.
+// The file's current hash is its version, so editing it shows up as an update.
+// @param baseDir folder relative paths are resolved against (the manifest's)
+// @param p path to the file
+// @return release or error if the file cannot be read
+func FromFile(baseDir, p string) (*Release, error) {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(baseDir, p)
+	}
+	sum, err := checksum.File(p)
+	if err != nil {
+		return nil, fmt.Errorf("local source: %w", err)
+	}
+	return &Release{
+		Version:       sum.SHA1,
+		VersionNumber: shortHash(sum.SHA1),
+		Filename:      filepath.Base(p),
+		Path:          p,
+		SHA1:          sum.SHA1,
+		SHA512:        sum.SHA512,
+	}, nil
+}
+
+func shortHash(sum string) string {
+	if len(sum) > 8 {
+		return sum[:8]
+	}
+	return sum
+}
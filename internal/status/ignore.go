@@ -0,0 +1,68 @@
+package status
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile sits next to project.json and lists untracked files to keep.
+const IgnoreFile = ".modignore"
+
+// Ignore holds the patterns of an ignore file. A nil *Ignore matches nothing.
+type Ignore struct {
+	patterns []string
+}
+
+// @brief LoadIgnore reads the ignore file of a pack folder. One glob per line
+// ("*.jar.bak", "mods/OptiFine*.jar"); a pattern without a slash matches file
+// names in any folder, one with a slash matches paths from the game dir.
+// Blank lines and lines starting with # are skipped.
+// @param dir folder holding project.json
+// @return patterns, nil if there is no ignore file, or error if a pattern is invalid
+func LoadIgnore(dir string) (*Ignore, error) {
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ign := &Ignore{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "/")
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", IgnoreFile, n, err)
+		}
+		ign.patterns = append(ign.patterns, line)
+	}
+	return ign, sc.Err()
+}
+
+// @brief Match reports whether a file is ignored.
+// @param rel slash path relative to the game dir
+func (ign *Ignore) Match(rel string) bool {
+	if ign == nil {
+		return false
+	}
+	for _, p := range ign.patterns {
+		target := rel
+		if !strings.Contains(p, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
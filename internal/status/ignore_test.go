@@ -0,0 +1,56 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	dir := t.TempDir()
+	file := "# kept by hand\n\n*.jar.bak\n/mods/OptiFine*.jar\nshaderpacks/*\n"
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFile), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ign, err := LoadIgnore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"mods/sodium.jar.bak", true},
+		{"resourcepacks/old.jar.bak", true}, // no slash: any folder
+		{"mods/OptiFine_1.20.1.jar", true},
+		{"mods/sub/OptiFine.jar", false}, // slash: from the game dir
+		{"mods/optifine.jar", false},
+		{"shaderpacks/BSL.zip", true},
+		{"mods/sodium.jar", false},
+		{"# kept by hand", false},
+	}
+	for _, tt := range tests {
+		if got := ign.Match(tt.rel); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+
+	var none *Ignore
+	if none.Match("mods/a.jar") {
+		t.Error("a nil Ignore matched")
+	}
+}
+
+func TestLoadIgnore(t *testing.T) {
+	dir := t.TempDir()
+	if ign, err := LoadIgnore(dir); ign != nil || err != nil {
+		t.Errorf("no ignore file: got %v, %v", ign, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFile), []byte("ok.jar\n[bad\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadIgnore(dir); err == nil {
+		t.Error("LoadIgnore accepted an invalid pattern")
+	}
+}
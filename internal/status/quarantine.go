@@ -0,0 +1,119 @@
+package status
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+)
+
+// quarantineDir holds pruned files in the state folder, one subfolder per prune.
+const quarantineDir = "quarantine"
+
+// Batch is the set of files one prune moved away.
+type Batch struct {
+	ID    string   // when the prune ran, "20060102-150405"
+	Files []string // slash paths relative to the game dir
+}
+
+// @brief QuarantineDir returns where pruned files of a game dir are kept.
+func QuarantineDir(gameDir string) string {
+	return filepath.Join(gameDir, overrides.StateDir, quarantineDir)
+}
+
+// @brief Prune moves files into a new quarantine batch, keeping their paths.
+// @param gameDir path to the game directory
+// @param files slash paths relative to the game dir
+// @return the batch (files moved before an error are in it), or error
+func Prune(gameDir string, files []string) (*Batch, error) {
+	id := time.Now().Format("20060102-150405")
+	root := filepath.Join(QuarantineDir(gameDir), id)
+	for n := 2; exists(root); n++ {
+		root = filepath.Join(QuarantineDir(gameDir), fmt.Sprintf("%s-%d", id, n))
+	}
+	b := &Batch{ID: filepath.Base(root)}
+	for _, rel := range files {
+		dst := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return b, err
+		}
+		if err := os.Rename(filepath.Join(gameDir, filepath.FromSlash(rel)), dst); err != nil {
+			return b, fmt.Errorf("failed to quarantine %s: %w", rel, err)
+		}
+		b.Files = append(b.Files, rel)
+	}
+	return b, nil
+}
+
+// @brief Batches lists the quarantined files of a game dir.
+// @param gameDir path to the game directory
+// @return batches, oldest first, or error
+func Batches(gameDir string) ([]Batch, error) {
+	dir := QuarantineDir(gameDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Batch
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		b := Batch{ID: e.Name()}
+		root := filepath.Join(dir, e.Name())
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			b.Files = append(b.Files, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(b.Files) > 0 {
+			out = append(out, b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// @brief Restore moves a batch's files back where they were. A file whose
+// place has been taken since stays in quarantine.
+// @param gameDir path to the game directory
+// @param b batch from Batches
+// @return restored and kept paths, or error
+func Restore(gameDir string, b Batch) ([]string, []string, error) {
+	root := filepath.Join(QuarantineDir(gameDir), b.ID)
+	var restored, kept []string
+	for _, rel := range b.Files {
+		dst := filepath.Join(gameDir, filepath.FromSlash(rel))
+		if exists(dst) {
+			kept = append(kept, rel)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return restored, kept, err
+		}
+		if err := os.Rename(filepath.Join(root, filepath.FromSlash(rel)), dst); err != nil {
+			return restored, kept, fmt.Errorf("failed to restore %s: %w", rel, err)
+		}
+		restored = append(restored, rel)
+	}
+	if len(kept) == 0 {
+		return restored, kept, os.RemoveAll(root) // only empty folders are left
+	}
+	return restored, kept, nil
+}
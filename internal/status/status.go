@@ -0,0 +1,197 @@
+package status
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/silask7188/ModrinthCLI/internal/manifest"
+	"github.com/silask7188/ModrinthCLI/internal/overrides"
+)
+
+// State says how a file on disk relates to the manifest.
+type State string
+
+const (
+	Tracked   State = "tracked"   // the entry's file, unchanged
+	Modified  State = "modified"  // the entry's file with other content, or active although the entry is off
+	Missing   State = "missing"   // the entry's file is not on disk
+	Disabled  State = "disabled"  // the entry's file, renamed to .disabled
+	Untracked State = "untracked" // no entry or override placed it
+	Ignored   State = "ignored"   // untracked, but matched by the ignore file
+)
+
+// File is one file of a destination folder.
+type File struct {
+	Name  string // file name, or the entry slug when no file name is recorded
+	Owner string // entry slug, "overrides", or "" for untracked files
+	State State
+}
+
+// Folder is one destination folder and its files, sorted by name.
+type Folder struct {
+	Dir   string // slash path relative to the game dir
+	Files []File
+}
+
+// @brief Count returns how many files of a folder are in a state.
+func (f Folder) Count(s State) int {
+	n := 0
+	for _, file := range f.Files {
+		if file.State == s {
+			n++
+		}
+	}
+	return n
+}
+
+// @brief Paths lists the files of a scan that are in a state.
+// @return slash paths relative to the game dir
+func Paths(folders []Folder, s State) []string {
+	var out []string
+	for _, f := range folders {
+		for _, file := range f.Files {
+			if file.State == s {
+				out = append(out, path.Join(f.Dir, file.Name))
+			}
+		}
+	}
+	return out
+}
+
+type folder struct {
+	ct     manifest.ContentType // decides which files count as content
+	claims map[string]File      // by file name
+}
+
+// @brief Scan compares every destination folder with the manifest.
+// Only files the folder's content type would load (.jar in mods/, .zip in
+// resourcepacks/, ...) can be untracked; settings and other files are left out.
+// @param gameDir path to the game directory
+// @param m manifest; its hash index is used and updated
+// @param ign ignore patterns, may be nil
+// @return folders sorted by path, or error if one could not be read
+func Scan(gameDir string, m *manifest.Manifest, ign *Ignore) ([]Folder, error) {
+	folders := map[string]*folder{}
+	use := func(dir string, ct manifest.ContentType) *folder {
+		f, ok := folders[dir]
+		if !ok {
+			f = &folder{ct: ct, claims: map[string]File{}}
+			folders[dir] = f
+		}
+		return f
+	}
+	claim := func(f *folder, file File) {
+		if _, taken := f.claims[file.Name]; !taken {
+			f.claims[file.Name] = file
+		}
+	}
+
+	// default folders, so untracked content shows up before anything is added
+	for _, ct := range manifest.ContentTypes() {
+		for _, dir := range manifest.Dirs(gameDir, ct, manifest.Entry{Dest: ct.Dest}) {
+			if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+				use(dir, ct)
+			}
+		}
+	}
+
+	side := m.TargetSide()
+	for _, ct := range manifest.ContentTypes() {
+		for _, e := range m.Entries(ct) {
+			active := e.Enable && e.SupportsSide(side)
+			for _, dir := range manifest.Dirs(gameDir, ct, e) {
+				f := use(dir, ct)
+				if e.Filename == "" {
+					if active {
+						claim(f, File{Name: e.Slug, Owner: e.Slug, State: Missing})
+					}
+					continue
+				}
+				file := File{Name: e.Filename, Owner: e.Slug}
+				plain := exists(filepath.Join(dir, e.Filename))
+				off := exists(filepath.Join(dir, e.Filename+".disabled"))
+				switch {
+				case active && plain:
+					file.State = Tracked
+					if !e.Sums().Empty() {
+						if ok, err := m.Hashes().Matches(filepath.Join(dir, e.Filename), e.Sums()); err != nil {
+							return nil, err
+						} else if !ok {
+							file.State = Modified
+						}
+					}
+				case active:
+					// renamed on disk, the next install records the new name
+					if found, err := m.Hashes().Find(dir, e.Sums(), ct.Accepts); err == nil {
+						file.Name, file.State = found, Tracked
+						if strings.HasSuffix(found, ".disabled") {
+							file.State = Disabled
+						}
+					} else if off {
+						file.Name, file.State = e.Filename+".disabled", Disabled
+					} else {
+						file.State = Missing
+					}
+				case plain:
+					file.State = Modified
+				case off:
+					file.Name, file.State = e.Filename+".disabled", Disabled
+				default:
+					continue // off and not on disk, as it should be
+				}
+				claim(f, file)
+			}
+		}
+	}
+
+	placed, err := overrides.Placed(gameDir)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Folder, 0, len(folders))
+	for dir, f := range folders {
+		rel, err := filepath.Rel(gameDir, dir)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, d := range entries {
+			name := d.Name()
+			if _, ok := f.claims[name]; ok || d.IsDir() || !f.ct.Accepts(name) {
+				continue
+			}
+			p := path.Join(rel, name)
+			switch {
+			case placed[p]:
+				f.claims[name] = File{Name: name, Owner: "overrides", State: Tracked}
+			case ign.Match(p):
+				f.claims[name] = File{Name: name, State: Ignored}
+			default:
+				f.claims[name] = File{Name: name, State: Untracked}
+			}
+		}
+
+		fo := Folder{Dir: rel}
+		for _, file := range f.claims {
+			fo.Files = append(fo.Files, file)
+		}
+		sort.Slice(fo.Files, func(i, j int) bool { return fo.Files[i].Name < fo.Files[j].Name })
+		out = append(out, fo)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Dir < out[j].Dir })
+	return out, nil
+}
+
+func exists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}